@@ -2,16 +2,38 @@ package main
 
 import (
 	"bufio"
+	"flag"
+	"fmt"
 	"io"
+	"math"
 	"os"
 	"strconv"
 	"strings"
 
-	"github.com/ajstarks/deck/generate"
+	"github.com/ajstarks/utils/locale"
+	"github.com/ajstarks/utils/markup"
+	"github.com/ajstarks/utils/scale"
+	"github.com/ajstarks/utils/settings"
+	"github.com/ajstarks/utils/source"
+	"github.com/ajstarks/utils/theme"
 )
 
+// ticks is the number of axis ticks to aim for
+const ticks = 5
+
+// defaults is the look without a theme: the first accent color is
+// the top of the bars, the foreground their sides
+var defaults = theme.Theme{
+	Background: "rgb(30,10,10)",
+	Foreground: "linen",
+	Accent:     []string{"maroon"},
+	Grid:       "linen",
+	Label:      theme.Font{Name: "sans", Size: 1.5},
+	Value:      theme.Font{Name: "sans", Size: 1.2},
+}
+
 // bar3d makes a 3D bar
-func bar3d(deck *generate.Deck, x, y, w, h float64, tcolor, lcolor string) {
+func bar3d(deck markup.Drawer, x, y, w, h float64, tcolor, lcolor string) {
 	wh := w / 2
 	th := w * 0.5
 	th2 := th / 2
@@ -27,16 +49,15 @@ func bar3d(deck *generate.Deck, x, y, w, h float64, tcolor, lcolor string) {
 	deck.Polygon(rightx, liney, lcolor, 60)
 }
 
-//vmap maps one interval to another
-func vmap(value float64, low1 float64, high1 float64, low2 float64, high2 float64) float64 {
-	return low2 + (high2-low2)*(value-low1)/(high1-low1)
+// bar is a labeled value
+type bar struct {
+	label string
+	value float64
 }
 
-// bardata reads data from the io.Reader, and plots bars
-func bardata(deck *generate.Deck, r io.Reader, left, bottom, top float64, tcolor, lcolor string) {
-	x := left
-	y := bottom
-	width := 5.0
+// readbars reads label, value pairs
+func readbars(r io.Reader) ([]bar, error) {
+	var bars []bar
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		fields := strings.Fields(scanner.Text())
@@ -47,18 +68,115 @@ func bardata(deck *generate.Deck, r io.Reader, left, bottom, top float64, tcolor
 		if err != nil {
 			continue
 		}
-		yp := vmap(value, 0, 200, 0, top-bottom)
-		bar3d(deck, x, y, width, yp, tcolor, lcolor)
-		deck.TextMid(x, y-2, fields[0], "sans", 1.5, "")
+		bars = append(bars, bar{label: fields[0], value: value})
+	}
+	return bars, scanner.Err()
+}
+
+// yscale makes the value scale: from zero (the smallest value for log scales)
+// to the largest value rounded out to a nice value, or to max if it is set
+func yscale(bars []bar, kind string, max, height float64) (scale.Scale, error) {
+	lo, hi := 0.0, max
+	if kind == "log" {
+		lo = math.Inf(1)
+	}
+	for _, b := range bars {
+		if max <= 0 {
+			hi = math.Max(hi, b.value)
+		}
+		if kind == "log" && b.value > 0 {
+			lo = math.Min(lo, b.value)
+		}
+	}
+	if math.IsInf(lo, 1) {
+		lo = 1
+	}
+	if hi <= lo {
+		hi = lo + 1
+	}
+	s, err := scale.New(kind, lo, hi, 0, height)
+	if err != nil {
+		return nil, err
+	}
+	if max <= 0 {
+		s.Nice(ticks)
+	}
+	return s, nil
+}
+
+// bardata reads data from the io.Reader, and plots bars on a labeled axis,
+// with their values if showvalues is set
+func bardata(deck markup.Drawer, r io.Reader, left, bottom, top, max float64, kind string, t theme.Theme, nf locale.Formatter, showvalues bool) error {
+	bars, err := readbars(r)
+	if err != nil {
+		return err
+	}
+	ys, err := yscale(bars, kind, max, top-bottom)
+	if err != nil {
+		return err
+	}
+	width := 5.0
+	right := left + width*float64(len(bars)-1)
+	for _, tk := range ys.Ticks(ticks) {
+		y := bottom + ys.Map(tk.Value)
+		deck.Line(left-width, y, right+width/2, y, 0.05, t.Grid, 30)
+		deck.TextEnd(left-width-1, y-0.5, nf.FormatTick(tk.Value, tk.Step), t.Value.Name, t.Value.Size, "")
+	}
+	x := left
+	for _, b := range bars {
+		if kind != "log" || b.value > 0 {
+			bar3d(deck, x, bottom, width, ys.Map(b.value), t.Color(0), t.Foreground)
+			if showvalues {
+				deck.TextMid(x, bottom+ys.Map(b.value)+1, nf.Format(b.value), t.Value.Name, t.Value.Size, t.Foreground)
+			}
+		}
+		deck.TextMid(x, bottom-2, b.label, t.Label.Name, t.Label.Size, "")
 		x += width
 	}
+	return nil
 }
 
 func main() {
-	deck := generate.NewSlides(os.Stdout, 0, 0)
+	style := flag.String("style", "deck", "output style (deck, decksh, svg)")
+	kind := flag.String("scale", "linear", "value scale (linear, log, symlog, sqrt)")
+	max := flag.Float64("max", 0, "maximum value (0 for the data maximum, rounded out)")
+	themename := flag.String("theme", "", "theme (light, dark, print, or a JSON file)")
+	showvalues := flag.Bool("values", false, "show the value of each bar")
+	localename := flag.String("locale", "", "locale for numbers (as en-US, de-DE or fr)")
+	numfmt := flag.String("numfmt", "plain", "number format (plain, group, si or percent, optionally :decimals)")
+	source.Flags()
+	settings.Parse()
+	if _, err := scale.New(*kind, 1, 10, 0, 1); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	t, err := theme.Choose(*themename, defaults)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	nf, err := locale.NewFormatter(*localename, *numfmt)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	r, err := source.Open(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	defer r.Close()
+	deck, err := markup.New(os.Stdout, *style, 0, 0)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
 	deck.StartDeck()
-	deck.StartSlide("rgb(30,10,10)", "linen")
-	bardata(deck, os.Stdin, 20, 10, 80, "maroon", "linen")
+	deck.StartSlide(t.SlideColors()...)
+	if err := bardata(deck, r, 20, 10, 80, *max, *kind, t, nf, *showvalues); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
 	deck.EndSlide()
 	deck.EndDeck()
 }
@@ -2,33 +2,55 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
 	"time"
 )
 
+// interval is the time between two dates, as written with -json
+type interval struct {
+	Begin string  `json:"begin"`
+	End   string  `json:"end"`
+	Unit  string  `json:"unit"`
+	Value float64 `json:"value"`
+	Error string  `json:"error,omitempty"`
+}
+
+// fail reports an error, and exits with the given status
+func fail(v interval, jsonout bool, status int, format string, args ...interface{}) {
+	if jsonout {
+		v.Error = fmt.Sprintf(format, args...)
+		json.NewEncoder(os.Stdout).Encode(v)
+	} else {
+		fmt.Fprintf(os.Stderr, format+"\n", args...)
+	}
+	os.Exit(status)
+}
+
 func main() {
 	const isofmt = "2006-01-02"
 	var begintime, endtime, unit string
+	var jsonout bool
 	flag.StringVar(&begintime, "begin", "", "begin time")
 	flag.StringVar(&endtime, "end", "", "end time")
 	flag.StringVar(&unit, "unit", "hour", "time unit (month, hour, minute, second, ms)")
+	flag.BoolVar(&jsonout, "json", false, "write JSON")
 	flag.Parse()
 
 	if begintime == "" || endtime == "" {
 		fmt.Fprintf(os.Stderr, "usage: between -begin YYYY-MM-DD -end YYYY-MM-DD -unit (hour, minute, or second\n")
 		os.Exit(1)
 	}
+	v := interval{Begin: begintime, End: endtime, Unit: unit}
 	t0, err := time.Parse(isofmt, begintime)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "%s is not a valid time\n", begintime)
-		os.Exit(2)
+		fail(v, jsonout, 2, "%s is not a valid time", begintime)
 	}
 	t1, err := time.Parse(isofmt, endtime)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "%s is not a valid time\n", endtime)
-		os.Exit(3)
+		fail(v, jsonout, 3, "%s is not a valid time", endtime)
 	}
 	var between float64
 	switch unit {
@@ -41,8 +63,12 @@ func main() {
 	case "ms":
 		between = float64(t1.Sub(t0).Milliseconds())
 	default:
-		fmt.Fprintf(os.Stderr, "%s is not a valid time unit (use one of hr, min, sec, ms)\n", unit)
-		os.Exit(4)
+		fail(v, jsonout, 4, "%s is not a valid time unit (use one of hr, min, sec, ms)", unit)
+	}
+	if jsonout {
+		v.Value = between
+		json.NewEncoder(os.Stdout).Encode(v)
+		return
 	}
 	fmt.Printf("%s %s %.2f %s\n", begintime, endtime, between, unit)
 }
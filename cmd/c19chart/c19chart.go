@@ -2,17 +2,18 @@
 package main
 
 import (
-	"bytes"
 	"encoding/json"
 	"flag"
 	"fmt"
-	"net/http"
 	"os"
-	"strconv"
 	"time"
 
 	"github.com/ajstarks/dchart2"
 	"github.com/ajstarks/deck/generate"
+	"github.com/ajstarks/utils/locale"
+	"github.com/ajstarks/utils/settings"
+	"github.com/ajstarks/utils/source"
+	"github.com/ajstarks/utils/theme"
 )
 
 const (
@@ -22,6 +23,24 @@ const (
 	c19Filename = "c19.csv"
 )
 
+// defaults is the look without a theme: cases are the first accent color,
+// deaths the second; latest values use the value font
+var defaults = theme.Theme{
+	Accent: []string{"rgb(100,100,100)", "maroon"},
+	Title:  theme.Font{Name: "sans", Size: 3.5},
+	Label:  theme.Font{Name: "sans", Size: 2.5},
+	Value:  theme.Font{Name: "sans", Size: 4},
+}
+
+// th is the theme
+var th = defaults
+
+// nf formats the latest values
+var nf, _ = locale.NewFormatter("", "group:0")
+
+// notesize is the size of notes: changes, ratios and legends
+func notesize() float64 { return th.Label.Size * 0.8 }
+
 type yrange struct {
 	min, max, step float64
 }
@@ -35,23 +54,18 @@ type C19 struct {
 	AllTimeConfirmed int      `json:"allTimeConfirmed"`
 }
 
-// makedata reads from the API, makes the CSV
-func makedata() error {
-	if t := fileage(); t < (8 * time.Hour) {
-		fmt.Fprintf(os.Stderr, "using the data file that is %v old\n", t)
-		return nil
+// makedata reads from the API, or the cache if it is recent, and makes the CSV
+func makedata(opener source.Opener) error {
+	if age, ok := opener.Age(c19URL); ok && age < opener.MaxAge {
+		fmt.Fprintf(os.Stderr, "using data that is %v old\n", age.Round(time.Second))
 	}
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Get(c19URL)
+	r, err := opener.Open(c19URL)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("bad response: %v from %s", resp.Status, c19URL)
-	}
+	defer r.Close()
 	var data C19
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
 		return err
 	}
 	w, err := os.Create(c19Filename)
@@ -86,35 +100,9 @@ func readChartData(s string) (dchart2.ChartBox, dchart2.ChartBox, error) {
 	return cc, dc, r.Close()
 }
 
-// ftoa converts a floating point value to string
-func ftoa(x float64, n int) string {
-	return strconv.FormatFloat(x, 'f', n, 64)
-}
-
-// https://stackoverflow.com/questions/13020308/how-to-fmt-printf-an-integer-with-thousands-comma
-func thousands(n float64, sep rune) string {
-	s := ftoa(n, 0)
-	startOffset := 0
-	var b bytes.Buffer
-
-	if n < 0 {
-		startOffset = 1
-		b.WriteByte('-')
-	}
-	l := len(s)
-	commaIndex := 3 - ((l - startOffset) % 3)
-	if commaIndex == 3 {
-		commaIndex = 0
-	}
-	for i := startOffset; i < l; i++ {
-		if commaIndex == 3 {
-			b.WriteRune(sep)
-			commaIndex = 0
-		}
-		commaIndex++
-		b.WriteByte(s[i])
-	}
-	return b.String()
+// percent formats a percentage with n decimals, in the locale of nf
+func percent(x float64, n int) string {
+	return locale.Formatter{Locale: nf.Locale, Style: "percent", Digits: n}.Format(x / 100)
 }
 
 // c19curve shows the covid-19 curve
@@ -127,9 +115,9 @@ func c19curve(deck *generate.Deck, chart dchart2.ChartBox, label, color string,
 	pv := chart.Data[dl-2].Value
 
 	pctchange := ((v - pv) / pv) * 100
-	deck.Text(left, ly, label, "sans", 2.5, color)
-	deck.Text(left+10, ly, thousands(v, ','), "sans", 4, color)
-	deck.TextEnd(chart.Right, ly, ftoa(pctchange, 3)+"% change", "sans", 2, chart.LabelColor)
+	deck.Text(left, ly, label, th.Label.Name, th.Label.Size, color)
+	deck.Text(left+10, ly, nf.Format(v), th.Value.Name, th.Value.Size, color)
+	deck.TextEnd(chart.Right, ly, percent(pctchange, 3)+" change", th.Label.Name, notesize(), chart.LabelColor)
 	chart.DataColor = color
 	chart.Frame(deck, 5)
 	chart.XLabel(deck, 5)
@@ -158,16 +146,16 @@ func summarychart(deck *generate.Deck, cc, dc dchart2.ChartBox, casecolor, death
 	//dc.Line(deck, 0.2)
 	dc.Opacity = 40
 	dc.Area(deck)
-	deck.Text(cc.Left+20, 15, "Cases", "sans", 2, casecolor)
-	deck.Text(cc.Right-10, 10, "Deaths", "sans", 2, deathcolor)
+	deck.Text(cc.Left+20, 15, "Cases", th.Label.Name, notesize(), casecolor)
+	deck.Text(cc.Right-10, 10, "Deaths", th.Label.Name, notesize(), deathcolor)
 }
 
 // labels makes chart labels
 func labels(deck *generate.Deck, cc, dc dchart2.ChartBox, y float64, color string) {
 	last := len(cc.Data) - 1
 	frate := (dc.Data[last].Value / cc.Data[last].Value) * 100
-	deck.Text(cc.Left, y, titlefmt+cc.Data[last].Label, "sans", 3.5, "")
-	deck.TextEnd(cc.Right, y, fatalfmt+ftoa(frate, 2)+"%", "sans", 2, color)
+	deck.Text(cc.Left, y, titlefmt+cc.Data[last].Label, th.Title.Name, th.Title.Size, "")
+	deck.TextEnd(cc.Right, y, fatalfmt+percent(frate, 2), th.Label.Name, notesize(), color)
 }
 
 func yrangeparse(s string) yrange {
@@ -179,28 +167,36 @@ func yrangeparse(s string) yrange {
 	return yr
 }
 
-func fileage() time.Duration {
-	f, err := os.Stat(c19Filename)
-	if err != nil {
-		return 8 * time.Hour
-	}
-	return time.Since(f.ModTime())
-}
-
 func main() {
 	var cyrs, dyrs string
 	flag.StringVar(&cyrs, "cyr", "0,7e6,1e6", "case y range")
 	flag.StringVar(&dyrs, "dyr", "0,4e5,1e5", "death y range")
-	flag.Parse()
-
+	themename := flag.String("theme", "", "theme (light, dark, print, or a JSON file)")
+	localename := flag.String("locale", "", "locale for numbers (as en-US, de-DE or fr)")
+	numfmt := flag.String("numfmt", "group:0", "number format (plain, group, si or percent, optionally :decimals)")
+	source.Default.MaxAge = 8 * time.Hour
+	source.Flags()
+	settings.Parse()
+
+	var err error
+	th, err = theme.Choose(*themename, defaults)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	nf, err = locale.NewFormatter(*localename, *numfmt)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
 	ty := 92.0
 	h := 20.0
-	casecolor := "rgb(100,100,100)"
-	deathcolor := "maroon"
+	casecolor := th.Color(0)
+	deathcolor := th.Color(1)
 	cyr := yrangeparse(cyrs)
 	dyr := yrangeparse(dyrs)
 
-	err := makedata()
+	err = makedata(source.Default)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
@@ -211,9 +207,13 @@ func main() {
 		os.Exit(2)
 	}
 
+	if len(th.Foreground) > 0 {
+		casesChart.LabelColor = th.Foreground
+		deathsChart.LabelColor = th.Foreground
+	}
 	deck := generate.NewSlides(os.Stdout, 0, 0)
 	deck.StartDeck()
-	deck.StartSlide()
+	deck.StartSlide(th.SlideColors()...)
 	labels(deck, casesChart, deathsChart, ty, deathcolor)
 	casesChart.Top = 85
 	c19curve(deck, casesChart, "Cases", casecolor, cyr, h)
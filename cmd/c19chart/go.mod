@@ -1,8 +1,27 @@
 module github.com/ajstarks/c19chart
 
-go 1.16
+go 1.21.6
 
 require (
 	github.com/ajstarks/dchart2 v0.0.0-20200422132333-d422fc36b888
 	github.com/ajstarks/deck/generate v0.0.0-20210223212949-8bd01c798494
+	github.com/ajstarks/utils/locale v0.0.0
+	github.com/ajstarks/utils/settings v0.0.0
+	github.com/ajstarks/utils/source v0.0.0
+	github.com/ajstarks/utils/theme v0.0.0
 )
+
+require (
+	github.com/ajstarks/deck v0.0.0-20200831202436-30c9fc6549a9 // indirect
+	github.com/ajstarks/utils/readpalette v0.0.0 // indirect
+)
+
+replace github.com/ajstarks/utils/theme => ../../theme
+
+replace github.com/ajstarks/utils/readpalette => ../../readpalette
+
+replace github.com/ajstarks/utils/settings => ../../settings
+
+replace github.com/ajstarks/utils/locale => ../../locale
+
+replace github.com/ajstarks/utils/source => ../../source
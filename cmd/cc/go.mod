@@ -1,3 +1,11 @@
 module github.com/ajstarks/utils/cmd/cc
 
-go 1.20
+go 1.21.6
+
+require github.com/ajstarks/utils/markup v0.0.0
+
+require github.com/ajstarks/utils/readpalette v0.0.0 // indirect
+
+replace github.com/ajstarks/utils/markup => ../../markup
+
+replace github.com/ajstarks/utils/readpalette => ../../readpalette
@@ -2,46 +2,33 @@
 package main
 
 import (
+	"flag"
 	"fmt"
-	"math"
+	"os"
+
+	"github.com/ajstarks/utils/markup"
 )
 
+// deck is the markup writer
+var deck markup.Drawer
+
+// canvas is the canvas size
+var canvas markup.Canvas
+
 // circle draws a circle
 func circle(x, y, size float64, color string) {
-	fmt.Printf("circle %.2f %.2f %.2f %q\n", x, y, size, color)
+	deck.Circle(x, y, size, color)
 }
 
 // cpolar places a circle at a polar coordinate
 func cpolar(x, y, r, t, size float64, color string) {
-	fmt.Printf("p=polar %.2f %.2f %.2f %.2f\ncircle p_x  p_y %.2f %q\n", x, y, r, t, size, color)
+	px, py := polar(x, y, r, t)
+	circle(px, py, size, color)
 }
 
-// polar returns Cartiesian coordinates from polar
+// polar returns Cartiesian coordinates from polar, corrected for aspect ratio
 func polar(x, y, r, deg float64) (float64, float64) {
-	theta := deg * (math.Pi / 180)
-	px := x + (r * math.Cos(theta))
-	py := y + (r * math.Sin(theta))
-	return px, py
-}
-
-// begindeck begins decksh markup
-func begindeck() {
-	fmt.Println("deck\ncanvas 1000 1000")
-}
-
-// enddeck end a deck
-func enddeck() {
-	fmt.Println("edeck")
-}
-
-// beginslide begins a slide
-func beginslide(color string) {
-	fmt.Printf("slide \"%s\"\n", color)
-}
-
-// endslide ends a slide
-func endslide() {
-	fmt.Println("eslide")
+	return canvas.Polar(x, y, r, deg)
 }
 
 // planet makes circles around a point
@@ -59,7 +46,7 @@ func solar(x, y, csize, radius, psize, steps float64, ccolor, pcolor string) {
 
 // d1 maes two concentric rings
 func d1(step float64) {
-	beginslide("black")
+	deck.StartSlide("black")
 	circle(50, 50, step, "red")
 	for t := 0.0; t <= 360; t += step {
 		px, py := polar(50, 50, 25, t)
@@ -69,7 +56,7 @@ func d1(step float64) {
 		px, py := polar(50, 50, 40, t)
 		solar(px, py, 5, 5, 1, step, "red", "orange")
 	}
-	endslide()
+	deck.EndSlide()
 }
 
 // hsv specifies a hue value in the hsv color space
@@ -79,7 +66,7 @@ func hsv(hue, sat, value int) string {
 
 // cchue makes a series of 7 concentric rings, varying bu nue
 func cchue(r, step float64, starthue int, bgcolor string) {
-	beginslide(bgcolor)
+	deck.StartSlide(bgcolor)
 	cstep := 1.0
 	c := 1.0
 	halfstep := step / 2
@@ -116,12 +103,22 @@ func cchue(r, step float64, starthue int, bgcolor string) {
 		px, py := polar(50, 50, r, t)
 		planet(px, py, 1, 5, 0, 360, 30, hsv(starthue, 100, 100))
 	}
-	endslide()
+	deck.EndSlide()
 }
 
 func main() {
-	begindeck()
+	style := flag.String("style", "decksh", "output style (deck, decksh, svg)")
+	flag.Float64Var(&canvas.Width, "w", 1000, "canvas width")
+	flag.Float64Var(&canvas.Height, "h", 1000, "canvas height")
+	flag.Parse()
+	var err error
+	deck, err = markup.New(os.Stdout, *style, int(canvas.Width), int(canvas.Height))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	deck.StartDeck()
 	cchue(10, 20, 0, "black")
 	d1(30)
-	enddeck()
+	deck.EndDeck()
 }
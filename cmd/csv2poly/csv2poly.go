@@ -111,8 +111,3 @@ func process(p params, filename string) error {
 	}
 	return r.Close()
 }
-
-// vmap maps one range to another
-func vmap(value, low1, high1, low2, high2 float64) float64 {
-	return low2 + (high2-low2)*(value-low1)/(high1-low1)
-}
@@ -7,16 +7,22 @@ Any output is ignored.
 The -t option shows the specified string before the time display,
 otherwise the first argument of the command is shown.
 
+The -json option writes the tag, command, time and any error as JSON.
+
 $ ctime sleep 10
 sleep	10.00685
 
 $ ctime -t "Go to sleep" sleep 5
 Go to sleep 5.00442
+
+$ ctime -json sleep 1
+{"tag":"sleep","command":["sleep","1"],"seconds":1.002310474}
 */
 
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
@@ -24,7 +30,15 @@ import (
 	"time"
 )
 
-func work(tag string, s []string) {
+// timing is a command's execution time, as written with -json
+type timing struct {
+	Tag     string   `json:"tag"`
+	Command []string `json:"command"`
+	Seconds float64  `json:"seconds"`
+	Error   string   `json:"error,omitempty"`
+}
+
+func work(tag string, s []string, jsonout bool) {
 	if len(s) < 1 {
 		return
 	}
@@ -34,6 +48,14 @@ func work(tag string, s []string) {
 	b := time.Now()
 	err := exec.Command(s[0], s[1:]...).Run()
 	e := time.Now()
+	if jsonout {
+		t := timing{Tag: tag, Command: s, Seconds: e.Sub(b).Seconds()}
+		if err != nil {
+			t.Error = err.Error()
+		}
+		json.NewEncoder(os.Stdout).Encode(t)
+		return
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		return
@@ -43,6 +65,7 @@ func work(tag string, s []string) {
 
 func main() {
 	var tag = flag.String("t", "", "tag for the command")
+	var jsonout = flag.Bool("json", false, "write JSON")
 	flag.Parse()
-	work(*tag, flag.Args())	
+	work(*tag, flag.Args(), *jsonout)
 }
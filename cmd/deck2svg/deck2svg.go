@@ -0,0 +1,320 @@
+// deck2svg -- render deck markup as SVG, one file per slide
+package main
+
+import (
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ajstarks/utils/markup"
+)
+
+// defaults for attributes that deck markup allows to be left out
+const (
+	shapecolor = "rgb(127,127,127)"
+	linewidth  = 0.2
+	textsize   = 1.0
+)
+
+// element is any deck element, with its attributes and text content
+type element struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Text    string     `xml:",chardata"`
+}
+
+// attr returns the named attribute, or the empty string
+func (e element) attr(name string) string {
+	for _, a := range e.Attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// num returns the named numeric attribute, or a default value
+func (e element) num(name string, def float64) float64 {
+	v, err := strconv.ParseFloat(strings.TrimSpace(e.attr(name)), 64)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// nums returns a list of numbers from a space separated attribute
+func (e element) nums(name string) []float64 {
+	f := strings.Fields(e.attr(name))
+	v := make([]float64, 0, len(f))
+	for _, s := range f {
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			continue
+		}
+		v = append(v, n)
+	}
+	return v
+}
+
+// color returns the color attribute, or a default
+func (e element) color(def string) string {
+	if c := e.attr("color"); len(c) > 0 {
+		return c
+	}
+	return def
+}
+
+// config holds the command options
+type config struct {
+	width, height int
+	outdir        string
+	stdout        bool
+}
+
+// renderer converts a deck to SVG
+type renderer struct {
+	cfg           config
+	base          string
+	width, height float64
+	out           markup.Drawer
+	file          *os.File
+	nslide        int
+	unknown       map[string]bool
+}
+
+// canvas sets the canvas size from the deck, unless set on the command line
+func (r *renderer) canvas(e element) {
+	if r.cfg.width > 0 && r.cfg.height > 0 {
+		return
+	}
+	w, h := e.num("width", 0), e.num("height", 0)
+	if w > 0 && h > 0 {
+		r.width, r.height = w, h
+	}
+}
+
+// startSlide begins a slide, opening its output file
+func (r *renderer) startSlide(e element) error {
+	r.nslide++
+	if !r.cfg.stdout || r.out == nil {
+		var w io.Writer = os.Stdout
+		if !r.cfg.stdout {
+			name := filepath.Join(r.cfg.outdir, fmt.Sprintf("%s-%05d.svg", r.base, r.nslide))
+			f, err := os.Create(name)
+			if err != nil {
+				return err
+			}
+			r.file, w = f, f
+		}
+		r.out = markup.NewSVG(w, int(r.width), int(r.height))
+		r.out.StartDeck()
+	}
+	r.out.StartSlide(e.attr("bg"), e.attr("fg"))
+	return nil
+}
+
+// endSlide ends a slide, writing its file
+func (r *renderer) endSlide() error {
+	if r.out == nil {
+		return nil
+	}
+	r.out.EndSlide()
+	if r.cfg.stdout {
+		return nil
+	}
+	r.out.EndDeck()
+	r.out = nil
+	return r.file.Close()
+}
+
+// endDeck finishes the stacked document written to standard output
+func (r *renderer) endDeck() {
+	if r.cfg.stdout && r.out != nil {
+		r.out.EndDeck()
+	}
+}
+
+// hr returns a height as a percentage of the canvas height, from a width
+// percentage and a height given relative to the width (percent)
+func (r *renderer) hr(w, hr float64) float64 {
+	return w * (hr / 100) * (r.width / r.height)
+}
+
+// draw renders one element
+func (r *renderer) draw(e element) {
+	d := r.out
+	op := e.num("opacity", 100)
+	x, y := e.num("xp", 0), e.num("yp", 0)
+	switch e.XMLName.Local {
+	case "text":
+		r.text(e, x, y, op)
+	case "rect":
+		w := e.num("wp", 0)
+		h := e.num("hp", 0)
+		if hr := e.num("hr", 0); hr > 0 {
+			h = r.hr(w, hr)
+		}
+		d.Rect(x, y, w, h, e.color(shapecolor), op)
+	case "ellipse":
+		w := e.num("wp", 0)
+		h := e.num("hp", 0)
+		if hr := e.num("hr", 0); hr > 0 {
+			h = r.hr(w, hr)
+		}
+		d.Ellipse(x, y, w, h, e.color(shapecolor), op)
+	case "line":
+		d.Line(e.num("xp1", 0), e.num("yp1", 0), e.num("xp2", 0), e.num("yp2", 0), e.num("sp", linewidth), e.color(shapecolor), op)
+	case "polygon":
+		d.Polygon(e.nums("xc"), e.nums("yc"), e.color(shapecolor), op)
+	case "polyline":
+		d.Polyline(e.nums("xc"), e.nums("yc"), e.num("sp", linewidth), e.color(shapecolor), op)
+	case "arc":
+		w := e.num("wp", 0)
+		d.Arc(x, y, w, e.num("hp", w), e.num("sp", linewidth), e.num("a1", 0), e.num("a2", 0), e.color(shapecolor), op)
+	case "image":
+		d.Image(x, y, int(e.num("width", 0)), int(e.num("height", 0)), e.num("scale", 100), e.attr("name"))
+	default:
+		if !r.unknown[e.XMLName.Local] {
+			fmt.Fprintf(os.Stderr, "deck2svg: %s elements are not supported\n", e.XMLName.Local)
+			r.unknown[e.XMLName.Local] = true
+		}
+	}
+}
+
+// text renders text, aligned, rotated, or as a block
+func (r *renderer) text(e element, x, y, op float64) {
+	d := r.out
+	s := strings.TrimSpace(e.Text)
+	font := e.attr("font")
+	size := e.num("sp", textsize)
+	color := e.attr("color")
+	align := e.attr("align")
+	if angle := e.num("rotation", 0); angle != 0 {
+		d.TextRotate(x, y, s, align, font, angle, size, color, op)
+		return
+	}
+	if e.attr("type") == "block" {
+		d.TextBlock(x, y, s, font, size, e.num("wp", 50), color, op)
+		return
+	}
+	switch align {
+	case "c", "center", "middle", "mid":
+		d.TextMid(x, y, s, font, size, color, op)
+	case "e", "end", "right":
+		d.TextEnd(x, y, s, font, size, color, op)
+	default:
+		d.Text(x, y, s, font, size, color, op)
+	}
+}
+
+// render reads deck markup and writes SVG
+func render(in io.Reader, base string, cfg config) error {
+	r := &renderer{cfg: cfg, base: base, unknown: make(map[string]bool)}
+	r.width, r.height = markup.DefaultWidth, markup.DefaultHeight
+	if cfg.width > 0 && cfg.height > 0 {
+		r.width, r.height = float64(cfg.width), float64(cfg.height)
+	}
+	dec := xml.NewDecoder(in)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "deck":
+				continue
+			case "slide":
+				var e element
+				e.Attrs = t.Attr
+				if err := r.startSlide(e); err != nil {
+					return err
+				}
+				continue
+			}
+			var e element
+			if err := dec.DecodeElement(&e, &t); err != nil {
+				return err
+			}
+			switch {
+			case e.XMLName.Local == "canvas":
+				r.canvas(e)
+			case r.out != nil:
+				r.draw(e)
+			}
+		case xml.EndElement:
+			if t.Name.Local == "slide" {
+				if err := r.endSlide(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	r.endDeck()
+	return nil
+}
+
+// basename makes the output file prefix from an input file name
+func basename(filename string) string {
+	if filename == "-" {
+		return "deck"
+	}
+	base := filepath.Base(filename)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// convert renders a deck file ("-" for standard input)
+func convert(filename string, cfg config) error {
+	if filename == "-" {
+		return render(os.Stdin, basename(filename), cfg)
+	}
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return render(f, basename(filename), cfg)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: deck2svg [options] [file...]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintf(os.Stderr, "Option    Default      Description\n")
+	fmt.Fprintf(os.Stderr, ".....................................................\n")
+	fmt.Fprintf(os.Stderr, "-w        deck canvas  canvas width (792 if the deck has no canvas)\n")
+	fmt.Fprintf(os.Stderr, "-h        deck canvas  canvas height (612 if the deck has no canvas)\n")
+	fmt.Fprintf(os.Stderr, "-outdir   .            output directory\n")
+	fmt.Fprintf(os.Stderr, "-stdout   false        write all slides as one SVG to standard output\n")
+}
+
+func main() {
+	var cfg config
+	flag.IntVar(&cfg.width, "w", 0, "canvas width")
+	flag.IntVar(&cfg.height, "h", 0, "canvas height")
+	flag.StringVar(&cfg.outdir, "outdir", ".", "output directory")
+	flag.BoolVar(&cfg.stdout, "stdout", false, "write to standard output")
+	flag.Usage = usage
+	flag.Parse()
+
+	files := flag.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	status := 0
+	for _, filename := range files {
+		if err := convert(filename, cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", filename, err)
+			status = 1
+		}
+	}
+	os.Exit(status)
+}
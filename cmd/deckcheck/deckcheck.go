@@ -0,0 +1,103 @@
+// deckcheck -- validate deck markup and decksh
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ajstarks/utils/deckcheck"
+)
+
+// exit status
+const (
+	clean    = 0 // no errors (warnings are allowed unless -strict)
+	problems = 1 // problems found
+	failed   = 2 // a file could not be read, or bad usage
+)
+
+type config struct {
+	strict bool
+	quiet  bool
+	format string
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: deckcheck [options] [file...]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintf(os.Stderr, "Option    Default    Description\n")
+	fmt.Fprintf(os.Stderr, ".....................................................\n")
+	fmt.Fprintf(os.Stderr, "-f        detect     format (xml, decksh)\n")
+	fmt.Fprintf(os.Stderr, "-strict   false      treat warnings as errors\n")
+	fmt.Fprintf(os.Stderr, "-q        false      quiet: report only through the exit status\n")
+}
+
+// check checks a file ("-" for standard input), reporting its problems,
+// and returns the number that count against it
+func check(filename string, cfg config) (int, error) {
+	var data []byte
+	var err error
+	dir := "."
+	if filename == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(filename)
+		dir = filepath.Dir(filename)
+	}
+	if err != nil {
+		return 0, err
+	}
+	format := deckcheck.Format(cfg.format)
+	if len(format) == 0 {
+		format = deckcheck.DetectFormat(filename, data)
+	}
+	found, err := deckcheck.Check(bytes.NewReader(data), format, dir)
+	if err != nil {
+		return 0, err
+	}
+	if !cfg.quiet {
+		for _, p := range found {
+			fmt.Printf("%s:%v\n", filename, p)
+		}
+	}
+	if cfg.strict {
+		return len(found), nil
+	}
+	return deckcheck.Errors(found), nil
+}
+
+func main() {
+	var cfg config
+	flag.StringVar(&cfg.format, "f", "", "format (xml, decksh)")
+	flag.BoolVar(&cfg.strict, "strict", false, "treat warnings as errors")
+	flag.BoolVar(&cfg.quiet, "q", false, "quiet")
+	flag.Usage = usage
+	flag.Parse()
+
+	switch deckcheck.Format(cfg.format) {
+	case "", deckcheck.XML, deckcheck.Decksh:
+	default:
+		fmt.Fprintf(os.Stderr, "unknown format %q (use xml or decksh)\n", cfg.format)
+		os.Exit(failed)
+	}
+	files := flag.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	status := clean
+	for _, filename := range files {
+		n, err := check(filename, cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", filename, err)
+			status = failed
+			continue
+		}
+		if n > 0 && status == clean {
+			status = problems
+		}
+	}
+	os.Exit(status)
+}
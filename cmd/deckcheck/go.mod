@@ -0,0 +1,11 @@
+module github.com/ajstarks/utils/cmd/deckcheck
+
+go 1.21.6
+
+require github.com/ajstarks/utils/deckcheck v0.0.0
+
+require github.com/ajstarks/utils/readpalette v0.0.0 // indirect
+
+replace github.com/ajstarks/utils/deckcheck => ../../deckcheck
+
+replace github.com/ajstarks/utils/readpalette => ../../readpalette
@@ -7,35 +7,28 @@ import (
 	"math/rand"
 	"os"
 
-	"github.com/ajstarks/deck/generate"
+	"github.com/ajstarks/utils/markup"
+	"github.com/ajstarks/utils/seeds"
 )
 
-func polygon(deck *generate.Deck, raw bool, x, y []float64, color string) {
-	if raw {
-		deck.Polygon(x, y, color)
-	} else {
-		fmt.Fprintf(os.Stdout, "polygon \"")
-		for i := 0; i < len(x); i++ {
-			fmt.Fprintf(os.Stdout, "%.4g ", x[i])
-		}
-		fmt.Fprintf(os.Stdout, "\" \"")
-		for i := 0; i < len(y); i++ {
-			fmt.Fprintf(os.Stdout, "%.4g ", y[i])
-		}
-		fmt.Fprintf(os.Stdout, "\" %q\n", color)
-	}
+// rng is the random number source, seeded for each slide
+var rng = rand.New(rand.NewSource(1))
+
+// deck is the markup writer
+var deck markup.Drawer
+
+// polygon makes a filled polygon
+func polygon(x, y []float64, color string) {
+	deck.Polygon(x, y, color)
 }
 
-func line(deck *generate.Deck, raw bool, x1, y1, x2, y2, linewidth float64, color string) {
-	if raw {
-		deck.Line(x1, y1, x2, y2, linewidth, color)
-	} else {
-		fmt.Fprintf(os.Stdout, "line %.4g %.4g %.4g %.4g %.4g %q\n", x1, y1, x2, y2, linewidth, color)
-	}
+// line makes a line
+func line(x1, y1, x2, y2, linewidth float64, color string) {
+	deck.Line(x1, y1, x2, y2, linewidth, color)
 }
 
 // hfill makes a (width long) horizontal deckled edge starting at (x,y)
-func hfill(deck *generate.Deck, mtype bool, x, y, width, height float64, color string, n int) {
+func hfill(x, y, width, height float64, color string, n int) {
 	xp := make([]float64, n)
 	yp := make([]float64, n)
 
@@ -54,13 +47,13 @@ func hfill(deck *generate.Deck, mtype bool, x, y, width, height float64, color s
 	xincr := width / float64(n)
 	for i := 1; i <= n-3; i++ {
 		xp[i] = xp[i-1] + xincr
-		yp[i] = y + rand.Float64()*height
+		yp[i] = y + rng.Float64()*height
 	}
-	polygon(deck, mtype, xp, yp, color)
+	polygon(xp, yp, color)
 }
 
 // vfill makes a (height high) vertical deckled edge
-func vfill(deck *generate.Deck, mtype bool, x, y, width, height float64, color string, n int) {
+func vfill(x, y, width, height float64, color string, n int) {
 	xp := make([]float64, n)
 	yp := make([]float64, n)
 
@@ -79,41 +72,41 @@ func vfill(deck *generate.Deck, mtype bool, x, y, width, height float64, color s
 	yincr := height / float64(n)
 	for i := 1; i <= n-3; i++ {
 		yp[i] = yp[i-1] + yincr
-		xp[i] = x + rand.Float64()*width
+		xp[i] = x + rng.Float64()*width
 	}
-	polygon(deck, mtype, xp, yp, color)
+	polygon(xp, yp, color)
 }
 
 // hline makes a (width long) horizontal deckled edge
-func hline(deck *generate.Deck, mtype bool, x, y, width, height, linewidth float64, color string, n int) {
+func hline(x, y, width, height, linewidth float64, color string, n int) {
 	xincr := width / float64(n)
 	hi := xincr / 2
 	y1 := y
 	for x1 := x; x1 < x+width; x1 += xincr {
-		y2 := y1 + rand.Float64()*height
-		line(deck, mtype, x1, y1, x1+(hi), y2, linewidth, color)
-		line(deck, mtype, x1+(hi), y2, x1+xincr, y1, linewidth, color)
+		y2 := y1 + rng.Float64()*height
+		line(x1, y1, x1+(hi), y2, linewidth, color)
+		line(x1+(hi), y2, x1+xincr, y1, linewidth, color)
 	}
 }
 
 // vline makes a (height high) vertical deckled edge
-func vline(deck *generate.Deck, mtype bool, x, y, width, height, linewidth float64, color string, n int) {
+func vline(x, y, width, height, linewidth float64, color string, n int) {
 	yincr := height / float64(n)
 	hi := yincr / 2
 	x1 := x
 	for y1 := y; y1 < y+height; y1 += yincr {
-		x2 := x1 + rand.Float64()*width
-		line(deck, mtype, x1, y1, x2, y1+(hi), linewidth, color)
-		line(deck, mtype, x2, y1+(hi), x1, y1+yincr, linewidth, color)
+		x2 := x1 + rng.Float64()*width
+		line(x1, y1, x2, y1+(hi), linewidth, color)
+		line(x2, y1+(hi), x1, y1+yincr, linewidth, color)
 	}
 }
 
 func main() {
-	deck := generate.NewSlides(os.Stdout, 0, 0)
 	var (
 		x, y, width, height, linewidth float64
-		n                              int
-		color, dtype                   string
+		n, variations                  int
+		seed                           int64
+		color, dtype, style            string
 		mtype                          bool
 	)
 
@@ -122,20 +115,50 @@ func main() {
 	flag.Float64Var(&width, "w", 80, "width")
 	flag.Float64Var(&height, "h", 3, "height")
 	flag.Float64Var(&linewidth, "lw", 0.1, "line width")
-	flag.BoolVar(&mtype, "raw", false, "type of markup - true for deck, false for decksh")
+	flag.BoolVar(&mtype, "raw", false, "deck markup (same as -style deck)")
+	flag.StringVar(&style, "style", "decksh", "output style (deck, decksh, svg)")
 	flag.IntVar(&n, "n", 50, "number of bumps")
 	flag.StringVar(&color, "color", "gray", "color")
 	flag.StringVar(&dtype, "type", "lh", "fv: filled vertical, fh: filled horizontal, lv: line vertical, lh: line horizontal")
+	flag.Int64Var(&seed, "seed", 0, "random seed (0 for a new seed each run)")
+	flag.IntVar(&variations, "variations", 1, "number of variations, one per slide")
 	flag.Parse()
 
-	switch dtype {
-	case "fv":
-		vfill(deck, mtype, x, y, width, height, color, n)
-	case "fh":
-		hfill(deck, mtype, x, y, width, height, color, n)
-	case "lv":
-		vline(deck, mtype, x, y, width, height, linewidth, color, n)
-	case "lh":
-		hline(deck, mtype, x, y, width, height, linewidth, color, n)
+	if mtype {
+		style = "deck"
+	}
+	var err error
+	if deck, err = markup.New(os.Stdout, style, 0, 0); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	// a single edge is a fragment to be included in a slide,
+	// unless it is SVG, which must be a complete document
+	slides := variations > 1 || style == "svg"
+	if slides {
+		deck.StartDeck()
+	}
+	for _, s := range seeds.Int64(seed, variations) {
+		rng = rand.New(rand.NewSource(s))
+		if slides {
+			deck.StartSlide()
+		}
+		deck.Comment(fmt.Sprintf("deckle -seed %d", s))
+		switch dtype {
+		case "fv":
+			vfill(x, y, width, height, color, n)
+		case "fh":
+			hfill(x, y, width, height, color, n)
+		case "lv":
+			vline(x, y, width, height, linewidth, color, n)
+		case "lh":
+			hline(x, y, width, height, linewidth, color, n)
+		}
+		if slides {
+			deck.EndSlide()
+		}
+	}
+	if slides {
+		deck.EndDeck()
 	}
 }
@@ -8,21 +8,25 @@ import (
 	"os"
 	"strconv"
 	"strings"
-)
 
-const (
-	linefmt   = "<line xp1=\"%v\" yp1=\"%v\" xp2=\"%v\" yp2=\"%v\" sp=\"%v\" color=%q/>\n"
-	squarefmt = "<rect xp=\"%v\" yp=\"%v\" wp=\"%v\" hr=\"100\" color=%q/>\n"
+	"github.com/ajstarks/utils/markup"
+	"github.com/ajstarks/utils/readpalette"
+	"github.com/ajstarks/utils/scale"
+	"github.com/ajstarks/utils/seeds"
 )
 
+// deck is the markup writer
+var deck markup.Drawer
+
+// registry holds the built-in palettes and those loaded from a palette file
+var registry = readpalette.Default()
+
+// rng is the random number source, seeded for each slide
+var rng = rand.New(rand.NewSource(1))
+
 // random returns a random number between a range
 func random(min, max float64) float64 {
-	return vmap(rand.Float64(), 0, 1, min, max)
-}
-
-// vmap maps one interval to another
-func vmap(value, low1, high1, low2, high2 float64) float64 {
-	return low2 + (high2-low2)*(value-low1)/(high1-low1)
+	return scale.NewLinear(0, 1, min, max).Map(rng.Float64())
 }
 
 // csquare makes a square with lines, using a specified width and color
@@ -31,8 +35,8 @@ func vmap(value, low1, high1, low2, high2 float64) float64 {
 // otherwise, the named color is used.
 func csquare(x, y, size, maxlw, h1, h2 float64, color string) {
 
-	if c, ok := palette[color]; ok { // use a palette
-		color = c[rand.Intn(len(c))]
+	if c, ok := registry.Lookup(color); ok { // use a palette
+		color = c[rng.Intn(len(c))]
 	}
 	if h1 > -1 && h2 > -1 { // hue range set
 		color = fmt.Sprintf("hsv(%v,100,100)", random(h1, h2))
@@ -59,17 +63,17 @@ func csquare(x, y, size, maxlw, h1, h2 float64, color string) {
 
 // square makes a square
 func square(x, y, size float64, color string) {
-	fmt.Printf(squarefmt, x, y, size, color)
+	deck.Square(x, y, size, color)
 }
 
 // hline makes a horizontal line
 func hline(x, y, size, lw float64, color string) {
-	fmt.Printf(linefmt, x, y, x+size, y, lw, color)
+	deck.Line(x, y, x+size, y, lw, color)
 }
 
 // vline makes a vertical line
 func vline(x, y, size, lw float64, color string) {
-	fmt.Printf(linefmt, x, y, x, y+size, lw, color)
+	deck.Line(x, y, x, y+size, lw, color)
 }
 
 // desordres makes a series of concentric squares
@@ -108,44 +112,50 @@ func usage() {
 	fmt.Fprintf(os.Stderr, "-maxlw      1           maximim line thickness\n")
 	fmt.Fprintf(os.Stderr, "-bgcolor    white       background color\n")
 	fmt.Fprintf(os.Stderr, "-p          \"\"          palette file\n")
+	fmt.Fprintf(os.Stderr, "-style      deck        output style (deck, decksh, svg)\n")
+	fmt.Fprintf(os.Stderr, "-seed       0           random seed (0 for a new seed each run)\n")
+	fmt.Fprintf(os.Stderr, "-variations 1           number of variations, one per slide\n")
 	fmt.Fprintf(os.Stderr, "-color      gray        color name, h1:h2, or palette:\n\n")
-	for p, k := range palette {
+	for _, p := range registry.Names() {
+		k, _ := registry.Lookup(p)
 		fmt.Fprintf(os.Stderr, "%-20s\t%v\n", p, k)
 	}
 	os.Exit(1)
 }
 
+// userpalette adds the palettes in a file to the registry
 func userpalette(pfile string) {
 	if len(pfile) > 0 {
-		var err error
-		palette, err = LoadPalette(pfile)
-		if err != nil {
+		if err := registry.LoadFile(pfile); err != nil {
 			fmt.Fprintf(os.Stderr, "%v\n", err)
 			os.Exit(1)
 		}
 	}
 }
 
-// slide generation functions
-func beginDeck()              { fmt.Println("<deck>") }
-func endDeck()                { fmt.Println("</deck>") }
-func beginSlide(color string) { fmt.Printf("<slide bg=%q>\n", color) }
-func endSlide()               { fmt.Println("</slide>") }
-
 func main() {
 	var tiles, maxlw float64
-	var bgcolor, color, pfile string
+	var bgcolor, color, pfile, style string
 	var showhelp bool
+	var seed int64
+	var variations int
 
 	flag.Float64Var(&tiles, "tiles", 10, "tiles/row")
 	flag.Float64Var(&maxlw, "maxlw", 1, "maximum line thickness")
 	flag.StringVar(&bgcolor, "bgcolor", "white", "background color")
 	flag.StringVar(&color, "color", "gray", "pen color: (named color, hue range (h1:h2), or palette name")
 	flag.StringVar(&pfile, "p", "", "palette file")
+	flag.StringVar(&style, "style", "deck", "output style (deck, decksh, svg)")
+	flag.Int64Var(&seed, "seed", 0, "random seed (0 for a new seed each run)")
+	flag.IntVar(&variations, "variations", 1, "number of variations")
 	flag.BoolVar(&showhelp, "help", false, "show usage")
 	flag.Parse()
 	h1, h2 := parseHues(color) // set hue range, or named color/palette
 	userpalette(pfile)
+	if len(registry.Resolve(color)) == 0 {
+		fmt.Fprintf(os.Stderr, "%s: palette has no colors\n", color)
+		os.Exit(1)
+	}
 	if showhelp {
 		usage()
 	}
@@ -154,13 +164,22 @@ func main() {
 	top := 100 - (size / 2) // top of the beginning row
 	left := 100 - top       // left of the beginning row
 
-	beginDeck()
-	beginSlide(bgcolor)
-	for y := top; y > 0; y -= size {
-		for x := left; x < 100; x += size {
-			desordres(x, y, 2, size, maxlw, h1, h2, color)
+	var err error
+	if deck, err = markup.New(os.Stdout, style, 0, 0); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	deck.StartDeck()
+	for _, s := range seeds.Int64(seed, variations) {
+		rng = rand.New(rand.NewSource(s))
+		deck.StartSlide(bgcolor)
+		deck.Comment(fmt.Sprintf("desordres -seed %d", s))
+		for y := top; y > 0; y -= size {
+			for x := left; x < 100; x += size {
+				desordres(x, y, 2, size, maxlw, h1, h2, color)
+			}
 		}
+		deck.EndSlide()
 	}
-	endSlide()
-	endDeck()
+	deck.EndDeck()
 }
@@ -1,3 +1,24 @@
 module dicechart
 
-go 1.19
+go 1.21.6
+
+require (
+	github.com/ajstarks/utils/locale v0.0.0
+	github.com/ajstarks/utils/markup v0.0.0
+	github.com/ajstarks/utils/readpalette v0.0.0
+	github.com/ajstarks/utils/settings v0.0.0
+	github.com/ajstarks/utils/source v0.0.0
+	github.com/ajstarks/utils/theme v0.0.0
+)
+
+replace github.com/ajstarks/utils/readpalette => ../../readpalette
+
+replace github.com/ajstarks/utils/markup => ../../markup
+
+replace github.com/ajstarks/utils/theme => ../../theme
+
+replace github.com/ajstarks/utils/settings => ../../settings
+
+replace github.com/ajstarks/utils/locale => ../../locale
+
+replace github.com/ajstarks/utils/source => ../../source
@@ -6,10 +6,15 @@ import (
 	"flag"
 	"fmt"
 	"io"
-	"math"
 	"os"
 	"strconv"
-	"strings"
+
+	"github.com/ajstarks/utils/locale"
+	"github.com/ajstarks/utils/markup"
+	"github.com/ajstarks/utils/readpalette"
+	"github.com/ajstarks/utils/settings"
+	"github.com/ajstarks/utils/source"
+	"github.com/ajstarks/utils/theme"
 )
 
 type dicedata struct {
@@ -32,6 +37,10 @@ type config struct {
 	dotsize     float64
 	dotcolor    string
 	title       string
+	style       string
+	remcolor    string
+	theme       theme.Theme
+	nf          locale.Formatter
 }
 
 const (
@@ -51,47 +60,36 @@ const (
 	legendy     = 5.0
 )
 
-// xmlmap defines the XML substitutions
-var xmlmap = strings.NewReplacer(
-	"&", "&amp;",
-	"<", "&lt;",
-	">", "&gt;")
-
-// xmlesc XML escapes a string
-func xmlesc(s string) string {
-	return xmlmap.Replace(s)
+// defaults is the look without a theme: dots are the first accent color,
+// the remainder die the second; text sizes are relative to the label size
+var defaults = theme.Theme{
+	Accent: []string{dotcolor, "red"},
+	Title:  theme.Font{Size: textsize * 1.5},
+	Label:  theme.Font{Size: textsize},
+	Value:  theme.Font{Size: valuesize},
 }
 
-// polar to Cartesian coordinates, corrected for aspect ratio
-func polar(cx, cy, r, theta, cw, ch float64) (float64, float64) {
-	ry := r * (cw / ch)
-	t := theta * (math.Pi / 180)
-	return cx + (r * math.Cos(t)), cy + (ry * math.Sin(t))
-}
+// design is the canvas the chart's spacing was laid out for
+var design = markup.Canvas{Width: cw, Height: ch}
 
-// beginDeck starts a deck
-func beginDeck(w io.Writer) {
-	fmt.Fprintln(w, "<deck><slide>")
+// canvas is the canvas the chart is drawn on
+func (cfg config) canvas() markup.Canvas {
+	return markup.Canvas{Width: cfg.cw, Height: cfg.ch}
 }
 
-// endDeck ends a deck
-func endDeck(w io.Writer) {
-	fmt.Fprintln(w, "</slide></deck>")
-}
-
-// text renders text at specified location and size
-func text(w io.Writer, s string, x, y, size float64) {
-	fmt.Fprintf(w, "<text xp=\"%v\" yp=\"%v\" sp=\"%v\">%s</text>\n", x, y, size, xmlesc(s))
+// text renders text at specified location, font and size
+func text(deck markup.Drawer, s string, x, y float64, font string, size float64) {
+	deck.Text(x, y, s, font, size, "")
 }
 
 // ctext makes centered text
-func ctext(w io.Writer, s string, x, y, size float64) {
-	fmt.Fprintf(w, "<text align=\"c\" xp=\"%v\" yp=\"%v\"sp=\"%v\">%s</text>\n", x, y, size, xmlesc(s))
+func ctext(deck markup.Drawer, s string, x, y float64, font string, size float64) {
+	deck.TextMid(x, y, s, font, size, "")
 }
 
 // circle makes a filled circle
-func circle(w io.Writer, x, y, r float64, color string) {
-	fmt.Fprintf(w, "<ellipse xp=\"%v\" yp=\"%v\" wp=\"%v\" hr=\"100\" color=%q/>\n", x, y, r, color)
+func circle(deck markup.Drawer, x, y, r float64, color string) {
+	deck.Circle(x, y, r, color)
 }
 
 // readData reads in name,value pairs in CSV format
@@ -119,17 +117,18 @@ func readData(r io.Reader) []dicedata {
 }
 
 // dicerow makes a labeled row of dice
-func dicerow(w io.Writer, d dicedata, cfg config, y float64) {
-	ly := y - (cfg.textsize / 3)
-	text(w, d.name, cfg.labelx, ly, cfg.textsize)
+func dicerow(deck markup.Drawer, d dicedata, cfg config, y float64) {
+	cv := cfg.canvas()
+	ly := y - cv.Relayout(cfg.textsize/3, design)
+	text(deck, d.name, cfg.labelx, ly, cfg.theme.Label.Name, cfg.textsize)
 	xp := cfg.datax
 	for i := 0; i < d.value/cfg.diceunit; i++ {
-		fivedots(w, xp, y, cfg.dicewidth, cfg.dotsize, cfg.dotcolor)
+		fivedots(deck, cv, xp, y, cfg.dicewidth, cfg.dotsize, cfg.dotcolor)
 		xp += cfg.dicespacing
 	}
 	rem := d.value % cfg.diceunit
-	dice(w, xp, y, cfg.dicewidth, cfg.dotsize, rem, "red")
-	legend(w, cfg)
+	dice(deck, cv, xp, y, cfg.dicewidth, cfg.dotsize, rem, cfg.remcolor)
+	legend(deck, cfg)
 
 	// nudge the value optimally next to the last block
 	var ns float64
@@ -144,76 +143,84 @@ func dicerow(w io.Writer, d dicedata, cfg config, y float64) {
 	case 3, 4:
 		ns = cfg.dicespacing / 2
 	}
-	text(w, strconv.Itoa(d.value), xp+ns, ly, cfg.valuesize)
+	text(deck, cfg.nf.Format(float64(d.value)), xp+ns, ly, cfg.theme.Value.Name, cfg.valuesize)
 }
 
-// dicechart reads data and makes the chart
-func dicechart(w io.Writer, r io.Reader, cfg config) {
+// dicechart reads data and makes the chart.
+// If the dot color names a palette, rows cycle through its colors.
+func dicechart(deck markup.Drawer, r io.Reader, cfg config) {
 	data := readData(r)
-	beginDeck(w)
+	colors := readpalette.Default().Resolve(cfg.dotcolor)
+	deck.StartDeck()
+	deck.StartSlide(cfg.theme.SlideColors()...)
 	if len(cfg.title) > 0 {
-		ctext(w, cfg.title, 50, cfg.top+(cfg.textsize*4), cfg.textsize*1.5)
+		title := cfg.theme.Title
+		ctext(deck, cfg.title, 50, cfg.top+(cfg.textsize*4), title.Name, title.Size*cfg.textsize/cfg.theme.Label.Size)
 	}
 	y := cfg.top
-	for _, d := range data {
-		dicerow(w, d, cfg, y)
+	for i, d := range data {
+		rowcfg := cfg
+		rowcfg.dotcolor = colors[i%len(colors)]
+		dicerow(deck, d, rowcfg, y)
 		y -= cfg.vskip
 	}
-	endDeck(w)
+	deck.EndSlide()
+	deck.EndDeck()
 }
 
 // dice makes a one, two, three, four, or five dot die.
-func dice(w io.Writer, x, y, r, size float64, n int, color string) {
-	x1, y1 := polar(x, y, r, 135, cw, ch) // top left
-	x2, y2 := polar(x, y, r, 225, cw, ch) // bottom left
-	x3, y3 := polar(x, y, r, 45, cw, ch)  // top right
-	x4, y4 := polar(x, y, r, 315, cw, ch) // bottom right
+func dice(deck markup.Drawer, cv markup.Canvas, x, y, r, size float64, n int, color string) {
+	x1, y1 := cv.Polar(x, y, r, 135) // top left
+	x2, y2 := cv.Polar(x, y, r, 225) // bottom left
+	x3, y3 := cv.Polar(x, y, r, 45)  // top right
+	x4, y4 := cv.Polar(x, y, r, 315) // bottom right
 	nd := n
 	if n > 5 {
 		nd = n / 5
 	}
 	switch nd {
 	case 1:
-		circle(w, x1, y1, size, color)
+		circle(deck, x1, y1, size, color)
 	case 2:
-		circle(w, x1, y1, size, color)
-		circle(w, x2, y2, size, color)
+		circle(deck, x1, y1, size, color)
+		circle(deck, x2, y2, size, color)
 	case 3:
-		circle(w, x1, y1, size, color)
-		circle(w, x2, y2, size, color)
-		circle(w, x3, y3, size, color)
+		circle(deck, x1, y1, size, color)
+		circle(deck, x2, y2, size, color)
+		circle(deck, x3, y3, size, color)
 	case 4:
-		circle(w, x1, y1, size, color)
-		circle(w, x2, y2, size, color)
-		circle(w, x3, y3, size, color)
-		circle(w, x4, y4, size, color)
+		circle(deck, x1, y1, size, color)
+		circle(deck, x2, y2, size, color)
+		circle(deck, x3, y3, size, color)
+		circle(deck, x4, y4, size, color)
 	case 5:
-		circle(w, x1, y1, size, color)
-		circle(w, x2, y2, size, color)
-		circle(w, x3, y3, size, color)
-		circle(w, x4, y4, size, color)
-		circle(w, x, y, size, color)
+		circle(deck, x1, y1, size, color)
+		circle(deck, x2, y2, size, color)
+		circle(deck, x3, y3, size, color)
+		circle(deck, x4, y4, size, color)
+		circle(deck, x, y, size, color)
 	}
 }
 
 // legend makes dice / unit legend
-func legend(w io.Writer, cfg config) {
-	ly := legendy - cfg.dotsize
-	fivedots(w, cfg.datax, legendy, cfg.dicewidth/2, cfg.dotsize/2, cfg.dotcolor)
-	text(w, strconv.Itoa(cfg.diceunit)+" items", cfg.datax+cfg.dicewidth, ly, cfg.textsize*0.7)
+func legend(deck markup.Drawer, cfg config) {
+	cv := cfg.canvas()
+	ly := legendy - cv.Relayout(cfg.dotsize, design)
+	fivedots(deck, cv, cfg.datax, legendy, cfg.dicewidth/2, cfg.dotsize/2, cfg.dotcolor)
+	text(deck, cfg.nf.Format(float64(cfg.diceunit))+" items", cfg.datax+cfg.dicewidth, ly, cfg.theme.Label.Name, cfg.textsize*0.7)
 }
 
 // fivedots makes a full 5-dot die
-func fivedots(w io.Writer, x, y, r, size float64, color string) {
-	x1, y1 := polar(x, y, r, 135, cw, ch) // top left
-	x2, y2 := polar(x, y, r, 225, cw, ch) // bottom left
-	x3, y3 := polar(x, y, r, 45, cw, ch)  // top right
-	x4, y4 := polar(x, y, r, 315, cw, ch) // bottom right
-	circle(w, x1, y1, size, color)
-	circle(w, x2, y2, size, color)
-	circle(w, x3, y3, size, color)
-	circle(w, x4, y4, size, color)
-	circle(w, x, y, size, color)
+func fivedots(deck markup.Drawer, cv markup.Canvas, x, y, r, size float64, color string) {
+	x1, y1 := cv.Polar(x, y, r, 135) // top left
+	x2, y2 := cv.Polar(x, y, r, 225) // bottom left
+	x3, y3 := cv.Polar(x, y, r, 45)  // top right
+	x4, y4 := cv.Polar(x, y, r, 315) // bottom right
+	circle(deck, x1, y1, size, color)
+	circle(deck, x2, y2, size, color)
+	circle(deck, x3, y3, size, color)
+	circle(deck, x4, y4, size, color)
+	circle(deck, x, y, size, color)
 }
 
 // setup processes command line flags and sets where data is read from
@@ -231,15 +238,30 @@ func setup() (config, io.Reader, error) {
 	flag.Float64Var(&cfg.dicewidth, "dw", dicewidth, "dice width")
 	flag.Float64Var(&cfg.dicespacing, "ds", dicespacing, "dice spacing")
 	flag.Float64Var(&cfg.dotsize, "dotsize", dotsize, "dot size")
-	flag.StringVar(&cfg.dotcolor, "color", dotcolor, "dotcolor")
+	flag.StringVar(&cfg.dotcolor, "color", dotcolor, "dot color or palette name")
 	flag.StringVar(&cfg.title, "title", "", "chart title")
-	flag.Parse()
+	flag.StringVar(&cfg.style, "style", "deck", "output style (deck, decksh, svg)")
+	themename := flag.String("theme", "", "theme (light, dark, print, or a JSON file)")
+	localename := flag.String("locale", "", "locale for numbers (as en-US, de-DE or fr)")
+	numfmt := flag.String("numfmt", "plain", "number format (plain, group, si or percent, optionally :decimals)")
+	source.Flags()
+	settings.Parse()
 
 	var err error
-	r := os.Stdin
-	if len(flag.Args()) > 0 {
-		r, err = os.Open(flag.Arg(0))
+	cfg.theme, err = theme.Choose(*themename, defaults)
+	if err != nil {
+		return cfg, nil, err
 	}
+	cfg.nf, err = locale.NewFormatter(*localename, *numfmt)
+	if err != nil {
+		return cfg, nil, err
+	}
+	theme.Use(&cfg.dotcolor, "color", cfg.theme.Color(0))
+	theme.Use(&cfg.textsize, "textsize", cfg.theme.Label.Size)
+	theme.Use(&cfg.valuesize, "valsize", cfg.theme.Value.Size)
+	cfg.remcolor = cfg.theme.Color(1)
+
+	r, err := source.Open(flag.Arg(0))
 	return cfg, r, err
 }
 
@@ -249,5 +271,10 @@ func main() {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
 	}
-	dicechart(os.Stdout, r, cfg)
+	deck, err := markup.New(os.Stdout, cfg.style, int(cfg.cw), int(cfg.ch))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	dicechart(deck, r, cfg)
 }
@@ -8,12 +8,9 @@ import (
 	"golang.org/x/net/dict"
 )
 
-var (
-	db      = flag.String("d", "wn", "Dictionary database")
-	dserver = flag.String("s", "dict.org:2628", "Dictionary Server")
-)
-
 func main() {
+	db := flag.String("d", "wn", "Dictionary database")
+	dserver := flag.String("s", "dict.org:2628", "Dictionary Server")
 	flag.Parse()
 	c, err := dict.Dial("tcp", *dserver)
 	if err != nil {
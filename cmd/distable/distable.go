@@ -10,6 +10,10 @@ import (
 	"strings"
 
 	"github.com/ajstarks/deck/generate"
+	"github.com/ajstarks/utils/locale"
+	"github.com/ajstarks/utils/settings"
+	"github.com/ajstarks/utils/source"
+	"github.com/ajstarks/utils/theme"
 )
 
 type place struct {
@@ -22,6 +26,21 @@ type distanceTable struct {
 	dist []place
 }
 
+// defaults is the look without a theme: place names use the label font,
+// distances the value font, and the rules are grid lines
+var defaults = theme.Theme{
+	Grid:  "gray",
+	Title: theme.Font{Name: "sans", Size: 3.5},
+	Label: theme.Font{Name: "serif", Size: 1.1},
+	Value: theme.Font{Name: "mono", Size: 1.1 * 0.65},
+}
+
+// th is the theme
+var th = defaults
+
+// nf formats the distances
+var nf, _ = locale.NewFormatter("", "plain:1")
+
 func main() {
 	var title, subtitle string
 	var left, top, size, dsize float64
@@ -31,7 +50,25 @@ func main() {
 	flag.Float64Var(&top, "top", 90, "top")
 	flag.Float64Var(&size, "size", 1.1, "text size")
 	flag.Float64Var(&dsize, "dsize", size*0.65, "distance text size")
-	flag.Parse()
+	themename := flag.String("theme", "", "theme (light, dark, print, or a JSON file)")
+	localename := flag.String("locale", "", "locale for numbers (as en-US, de-DE or fr)")
+	numfmt := flag.String("numfmt", "plain:1", "number format (plain, group, si or percent, optionally :decimals)")
+	source.Flags()
+	settings.Parse()
+
+	var err error
+	th, err = theme.Choose(*themename, defaults)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	nf, err = locale.NewFormatter(*localename, *numfmt)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	theme.Use(&size, "size", th.Label.Size)
+	theme.Use(&dsize, "dsize", th.Value.Size)
 	files := flag.Args()
 	deck := generate.NewSlides(os.Stdout, 0, 0)
 	deck.StartDeck()
@@ -49,23 +86,20 @@ func main() {
 // makeside makes the slide deck
 func makeslide(deck *generate.Deck, f string, w io.Writer, title, subtitle string, left, top, size, dsize float64) {
 	var data []distanceTable
-	var err error
-	var r io.Reader
-	if f == "-" {
-		r = os.Stdin
-	} else {
-		r, err = os.Open(f)
-		if err != nil {
-			return
-		}
+	r, err := source.Open(f)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return
 	}
+	defer r.Close()
 	data, err = readtable(r)
 	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
 		return
 	}
-	deck.StartSlide()
-	deck.Text(40, 89, title, "sans", 3.5, "")
-	deck.TextBlock(40, 85, subtitle, "serif", 1.5, 50, "")
+	deck.StartSlide(th.SlideColors()...)
+	deck.Text(40, 89, title, th.Title.Name, th.Title.Size, "")
+	deck.TextBlock(40, 85, subtitle, th.Label.Name, 1.5, 50, "")
 	distable(deck, data, left, top, size, dsize)
 	deck.EndSlide()
 }
@@ -129,8 +163,8 @@ func distable(deck *generate.Deck, table []distanceTable, left, top, size, dsize
 
 	// vertical column headings
 	for _, t := range table {
-		deck.TextRotate(x, y-vspacing, t.name, "", "serif", 90, size, "")
-		deck.Line(x-size-0.2, y-1, x-size-0.2, bottom, 0.05, "gray")
+		deck.TextRotate(x, y-vspacing, t.name, "", th.Label.Name, 90, size, "")
+		deck.Line(x-size-0.2, y-1, x-size-0.2, bottom, 0.05, th.Grid)
 		x += hspacing
 		y -= vspacing
 	}
@@ -139,16 +173,15 @@ func distable(deck *generate.Deck, table []distanceTable, left, top, size, dsize
 	y = top - vspacing
 	for _, t := range table {
 		// place names
-		deck.Text(x, y, t.name, "serif", size, "")
+		deck.Text(x, y, t.name, th.Label.Name, size, "")
 		dx := distleft
 		dy := y
 		// distances for each place
 		for _, d := range t.dist {
-			td := strconv.FormatFloat(d.distance, 'f', 1, 64)
-			deck.TextMid(dx, dy, td, "mono", dsize, "")
+			deck.TextMid(dx, dy, nf.Format(d.distance), th.Value.Name, dsize, "")
 			dx += hspacing
 		}
-		deck.Line(distleft-size, y-1, dx+size+0.3, y-1, 0.05, "gray")
+		deck.Line(distleft-size, y-1, dx+size+0.3, y-1, 0.05, th.Grid)
 		y -= vspacing
 	}
 }
@@ -1,7 +1,26 @@
 module github.com/ajstarks/utils/distable
 
-go 1.17
+go 1.21.6
 
-require github.com/ajstarks/deck/generate v0.0.0-20220116200525-3f887d0c5850
+require (
+	github.com/ajstarks/deck/generate v0.0.0-20220116200525-3f887d0c5850
+	github.com/ajstarks/utils/locale v0.0.0
+	github.com/ajstarks/utils/settings v0.0.0
+	github.com/ajstarks/utils/source v0.0.0
+	github.com/ajstarks/utils/theme v0.0.0
+)
 
-require github.com/ajstarks/deck v0.0.0-20200831202436-30c9fc6549a9 // indirect
+require (
+	github.com/ajstarks/deck v0.0.0-20200831202436-30c9fc6549a9 // indirect
+	github.com/ajstarks/utils/readpalette v0.0.0 // indirect
+)
+
+replace github.com/ajstarks/utils/theme => ../../theme
+
+replace github.com/ajstarks/utils/readpalette => ../../readpalette
+
+replace github.com/ajstarks/utils/settings => ../../settings
+
+replace github.com/ajstarks/utils/locale => ../../locale
+
+replace github.com/ajstarks/utils/source => ../../source
@@ -1,3 +1,12 @@
 module github.com/ajstarks/utils/cmd/dotspiral
 
-go 1.21.4
+go 1.21.6
+
+require (
+	github.com/ajstarks/utils/markup v0.0.0
+	github.com/ajstarks/utils/readpalette v0.0.0
+)
+
+replace github.com/ajstarks/utils/readpalette => ../../readpalette
+
+replace github.com/ajstarks/utils/markup => ../../markup
@@ -4,61 +4,48 @@ package main
 import (
 	"flag"
 	"fmt"
-)
+	"os"
 
-const (
-	deckfmt   = "deck\ncanvas %d %d\n"
-	circlefmt = "circle %.2f %.2f %.2f %q\n"
-	polarfmt  = "p=polar %.2f %.2f %.2f %.2f\ncircle p_x  p_y %.2f %q %.2f\n"
-	slidefmt  = "slide \"%s\"\n"
+	"github.com/ajstarks/utils/markup"
+	"github.com/ajstarks/utils/readpalette"
 )
 
 type config struct {
 	start, end, r, rincr, dincr, tincr, dotsize, dotop float64
-	dotcolor, bgcolor                                  string
+	width, height                                      float64
+	dotcolor, bgcolor, style                           string
 }
 
-// circle draws a circle
-func circle(x, y, size float64, color string) {
-	fmt.Printf(circlefmt, x, y, size, color)
-}
+// deck is the markup writer
+var deck markup.Drawer
+
+// canvas is the canvas size
+var canvas markup.Canvas
 
 // cpolar places a circle at a polar coordinate
 func cpolar(x, y, r, t, size float64, color string, op float64) {
-	fmt.Printf(polarfmt, x, y, r, t, size, color, op)
-}
-
-// begindeck begins decksh markup
-func begindeck(w, h int) {
-	fmt.Printf(deckfmt, w, h)
+	px, py := polar(x, y, r, t)
+	deck.Circle(px, py, size, color, op)
 }
 
-// enddeck end a deck
-func enddeck() {
-	fmt.Println("edeck")
+// polar returns Cartesian coordinates from polar, corrected for aspect ratio
+func polar(x, y, r, deg float64) (float64, float64) {
+	return canvas.Polar(x, y, r, deg)
 }
 
-// beginslide begins a slide
-func beginslide(color string) {
-	fmt.Printf(slidefmt, color)
-}
-
-// endslide ends a slide
-func endslide() {
-	fmt.Println("eslide")
-}
-
-// dotspiral makes a dot spiral
+// dotspiral makes a dot spiral; if the dot color names a palette,
+// the dots cycle through its colors
 func dotspiral(cx, cy float64, c config) {
 	r := c.r
 	dotsize := c.dotsize
-	beginslide(c.bgcolor)
-	for t := c.start; t <= c.end; t += c.tincr {
-		cpolar(cx, cy, r, t, dotsize, c.dotcolor, c.dotop)
+	colors := readpalette.Default().Resolve(c.dotcolor)
+	deck.StartSlide(c.bgcolor)
+	for i, t := 0, c.start; t <= c.end; i, t = i+1, t+c.tincr {
+		cpolar(cx, cy, r, t, dotsize, colors[i%len(colors)], c.dotop)
 		r += c.rincr
 		dotsize += c.dincr
 	}
-	endslide()
+	deck.EndSlide()
 }
 
 // configure set command line options
@@ -72,15 +59,26 @@ func configure() config {
 	flag.Float64Var(&c.dincr, "dincr", 0.5, "size increment")
 	flag.Float64Var(&c.dotsize, "size", 0.5, "dot size")
 	flag.Float64Var(&c.dotop, "op", 50, "dot opacity")
-	flag.StringVar(&c.dotcolor, "color", "red", "dot color")
+	flag.StringVar(&c.dotcolor, "color", "red", "dot color or palette name")
 	flag.StringVar(&c.bgcolor, "bgcolor", "white", "background color")
+	flag.StringVar(&c.style, "style", "decksh", "output style (deck, decksh, svg)")
+	flag.Float64Var(&c.width, "w", 500, "canvas width")
+	flag.Float64Var(&c.height, "h", 500, "canvas height")
 	flag.Parse()
 	return c
 
 }
 
 func main() {
-	begindeck(500, 500)
-	dotspiral(50, 50, configure())
-	enddeck()
+	c := configure()
+	var err error
+	canvas = markup.Canvas{Width: c.width, Height: c.height}
+	deck, err = markup.New(os.Stdout, c.style, int(c.width), int(c.height))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	deck.StartDeck()
+	dotspiral(50, 50, c)
+	deck.EndDeck()
 }
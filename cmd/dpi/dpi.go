@@ -1,26 +1,64 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
 	"fmt"
 	"math"
 	"os"
 	"strconv"
 )
 
+// display describes a display, as written with -json
+type display struct {
+	Device string  `json:"device,omitempty"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+	Diag   float64 `json:"diag"`
+	Aspect float64 `json:"aspect"`
+	DPI    float64 `json:"dpi"`
+	Error  string  `json:"error,omitempty"`
+}
+
 func dpi(w, h, d float64) float64 {
 	return math.Sqrt((w*w)+(h*h)) / d
 }
 
 func main() {
-	if len(os.Args) < 4 {
-		println("Usage: dpi w h diag [device]")
+	jsonout := flag.Bool("json", false, "write JSON")
+	flag.Parse()
+	args := flag.Args()
+	if len(args) < 3 {
+		println("Usage: dpi [-json] w h diag [device]")
 		os.Exit(1)
 	}
-	w, _ := strconv.ParseFloat(os.Args[1], 64)
-	h, _ := strconv.ParseFloat(os.Args[2], 64)
-	d, _ := strconv.ParseFloat(os.Args[3], 64)
-	if len(os.Args) == 5 {
-		fmt.Printf("%s ", os.Args[4])
+	w, werr := strconv.ParseFloat(args[0], 64)
+	h, herr := strconv.ParseFloat(args[1], 64)
+	d, derr := strconv.ParseFloat(args[2], 64)
+	if *jsonout {
+		var v display
+		if len(args) == 4 {
+			v.Device = args[3]
+		}
+		switch {
+		case werr != nil:
+			v.Error = werr.Error()
+		case herr != nil:
+			v.Error = herr.Error()
+		case derr != nil:
+			v.Error = derr.Error()
+		case h <= 0 || d <= 0:
+			v.Width, v.Height, v.Diag = w, h, d
+			v.Error = "height and diagonal must be positive"
+		default:
+			v.Width, v.Height, v.Diag = w, h, d
+			v.Aspect, v.DPI = w/h, dpi(w, h, d)
+		}
+		json.NewEncoder(os.Stdout).Encode(v)
+		return
+	}
+	if len(args) == 4 {
+		fmt.Printf("%s ", args[3])
 	}
 	if h > 0 && d > 0 {
 		fmt.Printf("w=%.0f h=%0.f diag=%.2f aspect=%.2f dpi=%.2f\n", w, h, d, w/h, dpi(w, h, d))
@@ -8,10 +8,16 @@ import (
 	"flag"
 	"fmt"
 	"io"
-	"math"
 	"os"
 	"strconv"
 	"strings"
+
+	"github.com/ajstarks/utils/locale"
+	"github.com/ajstarks/utils/markup"
+	"github.com/ajstarks/utils/readpalette"
+	"github.com/ajstarks/utils/settings"
+	"github.com/ajstarks/utils/source"
+	"github.com/ajstarks/utils/theme"
 )
 
 // Measure describes the data set
@@ -28,88 +34,75 @@ type Dataset struct {
 }
 
 const (
-	midx          = 50.0            // middle of the canvas
-	midy          = 50.0            // middle of the canvas
-	ty            = 95.0            // title y coordinate
-	arcsize       = 30.0            // size of the wedges
-	titlesize     = 3.0             // text size of titles
-	catsize       = 2.0             // size of data category label
-	legendsize    = 1.8             // legend label size
-	labelsize     = 1.5             // data label size
-	notesize      = titlesize * 0.6 // text size of footnotes
-	topbegAngle   = 145.0           // top beginning angle
-	botbegAngle   = 215.0           // bottom beginning angle
-	fanspan       = 110.0           // span size of the top and bottom of the fan
-	leftbegAngle  = 135.0           // left beginning angle
-	rightbegAngle = 315.0           // right beginning angle
-	wingspan      = 90.0            // span size of the left and right wings
+	midx          = 50.0  // middle of the canvas
+	midy          = 50.0  // middle of the canvas
+	ty            = 95.0  // title y coordinate
+	arcsize       = 30.0  // size of the wedges
+	topbegAngle   = 145.0 // top beginning angle
+	botbegAngle   = 215.0 // bottom beginning angle
+	fanspan       = 110.0 // span size of the top and bottom of the fan
+	leftbegAngle  = 135.0 // left beginning angle
+	rightbegAngle = 315.0 // right beginning angle
+	wingspan      = 90.0  // span size of the left and right wings
 )
 
-// xmlmap defines the XML substitutions
-var xmlmap = strings.NewReplacer(
-	"&", "&amp;",
-	"<", "&lt;",
-	">", "&gt;")
-
-// xmlesc XML escapes a string
-func xmlesc(s string) string {
-	return xmlmap.Replace(s)
+// defaults is the look without a theme: titles and footnotes use the title
+// font, category and legend labels the label font, and data labels the value font
+var defaults = theme.Theme{
+	Background: "white",
+	Foreground: "black",
+	Title:      theme.Font{Size: 3},
+	Label:      theme.Font{Size: 2},
+	Value:      theme.Font{Size: 1.5},
 }
 
+// deck is the markup writer
+var deck markup.Drawer
+
+// design is the canvas the legend's spacing was laid out for
+var design = markup.Canvas{Width: 792, Height: 612}
+
+// th is the theme
+var th = defaults
+
+// nf formats the data values, which are percentages
+var nf, _ = locale.NewFormatter("", "percent:1")
+
+// legendsize is the size of legend labels, a little smaller than other labels
+func legendsize() float64 { return th.Label.Size * 0.9 }
+
 // title makes a title
 func title(s string) {
-	ctext(s, midx, ty, titlesize)
+	ctext(s, midx, ty, th.Title.Name, th.Title.Size)
 }
 
 // arc draws a filled arc
 func arc(cx, cy, a1, a2, size float64, color string) {
-	fmt.Printf(
-		"<arc xp=\"%.2f\" yp=\"%.2f\" wp=\"%.2f\" hp=\"%.2f\" a1=\"%.2f\" a2=\"%.2f\" sp=\"%.2f\" color=%q/>\n",
-		cx, cy, size, size, a1, a2, size, color)
+	deck.Arc(cx, cy, size, size, size, a1, a2, color)
 }
 
 // circle makes a filled circle
 func circle(x, y, r float64, color string) {
-	fmt.Printf("<ellipse xp=\"%v\" yp=\"%v\" wp=\"%v\" hr=\"100\" color=%q/>\n", x, y, r, color)
+	deck.Circle(x, y, r, color)
 }
 
-// text renders text at specified location and size
-func text(s string, x, y, size float64) {
-	fmt.Printf("<text xp=\"%v\" yp=\"%v\" sp=\"%v\">%s</text>\n", x, y, size, xmlesc(s))
+// text renders text at specified location, font and size
+func text(s string, x, y float64, font string, size float64) {
+	deck.Text(x, y, s, font, size, "")
 }
 
-// etext renders text at specified location and size, end justified
-func etext(s string, x, y, size float64) {
-	fmt.Printf("<text align=\"e\" xp=\"%v\" yp=\"%v\" sp=\"%v\">%s</text>\n", x, y, size, xmlesc(s))
+// etext renders text at specified location, font and size, end justified
+func etext(s string, x, y float64, font string, size float64) {
+	deck.TextEnd(x, y, s, font, size, "")
 }
 
 // ctext makes centered text
-func ctext(s string, x, y, size float64) {
-	fmt.Printf("<text align=\"c\" xp=\"%v\" yp=\"%v\"sp=\"%v\">%s</text>\n", x, y, size, xmlesc(s))
-}
-
-// beginDeck makes the markup to begin a deck
-func beginDeck(w, h float64) {
-	fmt.Printf("<deck>\n<canvas width=\"%v\" height=\"%v\"/>\n", w, h)
-}
-
-// beginSlide makes the markup to begin a slide
-func beginSlide(bgcolor, textcolor string) {
-	fmt.Printf("<slide bg=%q fg=%q>\n", bgcolor, textcolor)
-}
-
-// endSlide makes the markup to end a slide
-func endSlide() {
-	fmt.Println("</slide>")
-}
-
-// beginDeck makes the markup to begin a deck
-func endDeck() {
-	fmt.Println("</deck>")
+func ctext(s string, x, y float64, font string, size float64) {
+	deck.TextMid(x, y, s, font, size, "")
 }
 
 // legend makes a balanced left and right hand legend
-func legend(data []Measure, orientation string, ts float64) {
+func legend(data []Measure, orientation string, ts float64, cv markup.Canvas) {
 	var x, y, xoffset float64
 	l := len(data)
 	h := l / 2
@@ -117,7 +110,7 @@ func legend(data []Measure, orientation string, ts float64) {
 	hr := h + rem
 
 	r := ts + 1.0
-	leading := ts * 6
+	leading := cv.Relayout(ts*6, design)
 
 	switch orientation {
 	case "tb":
@@ -132,7 +125,7 @@ func legend(data []Measure, orientation string, ts float64) {
 	for i := 0; i < hr; i++ {
 		label := data[i].name
 		circle(x, y, r, data[i].color)
-		legendlabel(label, x+xoffset, y, ts)
+		legendlabel(label, x+xoffset, y, ts, cv)
 		y -= leading
 	}
 	// right/bottom legend
@@ -147,83 +140,78 @@ func legend(data []Measure, orientation string, ts float64) {
 	for i := hr; i < len(data); i++ {
 		label := data[i].name
 		circle(x, y, r, data[i].color)
-		legendlabel(label, x+xoffset, y, ts)
+		legendlabel(label, x+xoffset, y, ts, cv)
 		y -= leading
 	}
 }
 
 // legendlabel lays out the legend labels
-func legendlabel(s string, x, y, ts float64) {
+func legendlabel(s string, x, y, ts float64, cv markup.Canvas) {
 	w := strings.Split(s, `\n`)
 	lw := len(w)
 	if lw == 1 {
-		text(s, x, y-(ts/3), ts)
+		text(s, x, y-cv.Relayout(ts/3, design), th.Label.Name, ts)
 	} else {
-		y = y + (ts * (float64(lw / 3)))
+		y = y + cv.Relayout(ts*(float64(lw/3)), design)
 		for i := 0; i < lw; i++ {
-			text(w[i], x, y, ts)
-			y -= (ts * 1.8)
+			text(w[i], x, y, th.Label.Name, ts)
+			y -= cv.Relayout(ts*1.8, design)
 		}
 	}
 }
 
 // arclabel labels the data items
-func arclabel(cx, cy, a1, a2, asize, value, cw, ch float64) {
-	v := strconv.FormatFloat(value, 'f', 1, 64)
+func arclabel(cx, cy, a1, a2, asize, value float64, cv markup.Canvas) {
 	diff := a2 - a1
-	lx, ly := polar(cx, cy, asize*0.9, a1+(diff*0.5), cw, ch)
-	ctext(v+"%", lx, ly, labelsize)
-}
-
-// polar to Cartesian coordinates, corrected for aspect ratio
-func polar(cx, cy, r, theta, cw, ch float64) (float64, float64) {
-	ry := r * (cw / ch)
-	t := theta * (math.Pi / 180)
-	return cx + (r * math.Cos(t)), cy + (ry * math.Sin(t))
+	lx, ly := cv.Polar(cx, cy, asize*0.9, a1+(diff*0.5))
+	if nf.Style == "percent" {
+		value /= 100
+	}
+	ctext(nf.Format(value), lx, ly, th.Value.Name, th.Value.Size)
 }
 
 // wedge makes data wedges
-func wedge(data Dataset, cx, cy, begAngle, asize, cw, ch float64) {
+func wedge(data Dataset, cx, cy, begAngle, asize float64, cv markup.Canvas) {
 	start := begAngle
 	for _, d := range data.measures {
 		m := (d.value / 100) * wingspan
 		a1 := start
 		a2 := start + m
 		arc(cx, cy, a1, a2, asize, d.color)
-		arclabel(cx, cy, a1, a2, asize, d.value, cw, ch)
+		arclabel(cx, cy, a1, a2, asize, d.value, cv)
 		start = a2
 	}
 }
 
 // wings makes left and right data "wings"
-func wings(top, bot Dataset, cx, cy, asize, cw, ch float64) {
+func wings(top, bot Dataset, cx, cy, asize float64, cv markup.Canvas) {
 	var lx, ly float64
-	lx, ly = polar(cx, cy, asize+1, 180, cw, ch)
-	etext(top.name, lx, ly, legendsize)
-	wedge(top, cx, cy, leftbegAngle, asize, cw, ch)
-	lx, ly = polar(cx, cy, asize+1, 0, cw, ch)
-	text(bot.name, lx, ly, legendsize)
-	wedge(bot, cx, cy, rightbegAngle, asize, cw, ch)
+	lx, ly = cv.Polar(cx, cy, asize+1, 180)
+	etext(top.name, lx, ly, th.Label.Name, legendsize())
+	wedge(top, cx, cy, leftbegAngle, asize, cv)
+	lx, ly = cv.Polar(cx, cy, asize+1, 0)
+	text(bot.name, lx, ly, th.Label.Name, legendsize())
+	wedge(bot, cx, cy, rightbegAngle, asize, cv)
 }
 
 // fan makes the top and bottom fan
-func fan(top, bot Dataset, cx, cy, asize, cw, ch float64) {
+func fan(top, bot Dataset, cx, cy, asize float64, cv markup.Canvas) {
 	var lx, ly, start float64
 	// the top of the fan chart
-	lx, ly = polar(cx, cy, asize+1, 90, cw, ch)
-	ctext(top.name, lx, ly, catsize)
+	lx, ly = cv.Polar(cx, cy, asize+1, 90)
+	ctext(top.name, lx, ly, th.Label.Name, th.Label.Size)
 	start = topbegAngle
 	for _, d := range top.measures {
 		m := (d.value / 100) * fanspan
 		a1 := start - m
 		a2 := start
 		arc(cx, cy, a1, a2, asize, d.color)
-		arclabel(cx, cy, a1, a2, asize, d.value, cw, ch)
+		arclabel(cx, cy, a1, a2, asize, d.value, cv)
 		start = a1
 	}
 	// bottom of the fan chart
-	lx, ly = polar(cx, cy, asize+2, 270, cw, ch)
-	ctext(bot.name, lx, ly, catsize)
+	lx, ly = cv.Polar(cx, cy, asize+2, 270)
+	ctext(bot.name, lx, ly, th.Label.Name, th.Label.Size)
 	start = botbegAngle
 	for i := len(bot.measures) - 1; i >= 0; i-- {
 		d := bot.measures[i]
@@ -231,7 +219,7 @@ func fan(top, bot Dataset, cx, cy, asize, cw, ch float64) {
 		a1 := start + m
 		a2 := start
 		arc(cx, cy, a2, a1, asize, d.color)
-		arclabel(cx, cy, a1, a2, asize, d.value, cw, ch)
+		arclabel(cx, cy, a1, a2, asize, d.value, cv)
 		start = a1
 	}
 }
@@ -250,10 +238,11 @@ func readData(filename string) (Dataset, Dataset, error) {
 	var topdata, botdata Dataset
 	var td, bd Measure
 	var tds, bds []Measure
-	r, err := os.Open(filename)
+	r, err := source.Open(filename)
 	if err != nil {
 		return topdata, botdata, err
 	}
+	defer r.Close()
 	input := csv.NewReader(r)
 	n := 0
 	topcount := 0
@@ -317,6 +306,13 @@ func readData(filename string) (Dataset, Dataset, error) {
 	return topdata, botdata, nil
 }
 
+// recolor colors the measures from a palette, in order
+func recolor(data Dataset, colors []string) {
+	for i := range data.measures {
+		data.measures[i].color = colors[i%len(colors)]
+	}
+}
+
 // newset determines if a new set of data has begun in the input
 func isheader(s []string) bool {
 	return len(s[1]) == 0 && len(s[2]) == 0
@@ -324,12 +320,12 @@ func isheader(s []string) bool {
 
 // note makes a footnote
 func note(s string) {
-	ctext(s, 50, 3, notesize)
+	ctext(s, 50, 3, th.Title.Name, th.Title.Size*0.6)
 }
 
 func main() {
 	var canvasWidth, canvasHeight, arcsize float64
-	var orientation, textcolor, bgcolor string
+	var orientation, textcolor, bgcolor, palette, style string
 
 	flag.Float64Var(&canvasHeight, "h", 612, "canvas height") // canvas height
 	flag.Float64Var(&canvasWidth, "w", 792, "canvas width")   // canvas width
@@ -337,24 +333,69 @@ func main() {
 	flag.StringVar(&orientation, "dir", "tb", "orientation (tb=Top/Bottom, lr=Left/Right)")
 	flag.StringVar(&bgcolor, "bgcolor", "white", "background color")
 	flag.StringVar(&textcolor, "textcolor", "black", "text color")
+	flag.StringVar(&palette, "color", "", "palette name (overrides the data colors)")
+	flag.StringVar(&style, "style", "deck", "output style (deck, decksh, svg)")
+	themename := flag.String("theme", "", "theme (light, dark, print, or a JSON file)")
+	localename := flag.String("locale", "", "locale for numbers (as en-US, de-DE or fr)")
+	numfmt := flag.String("numfmt", "percent:1", "number format (plain, group, si or percent, optionally :decimals)")
+
+	source.Flags()
+	settings.Parse()
 
-	flag.Parse()
+	var err error
+	th, err = theme.Choose(*themename, defaults)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	nf, err = locale.NewFormatter(*localename, *numfmt)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	theme.Use(&bgcolor, "bgcolor", th.Background)
+	theme.Use(&textcolor, "textcolor", th.Foreground)
+
+	// theme accents replace the data colors, and a palette replaces both
+	colors := th.Accent
+	if len(palette) > 0 {
+		var ok bool
+		colors, ok = readpalette.Default().Lookup(palette)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown palette %q\n", palette)
+			os.Exit(1)
+		}
+	}
 
-	beginDeck(canvasWidth, canvasHeight)
-	for _, f := range flag.Args() {
-		beginSlide(bgcolor, textcolor)
+	deck, err = markup.New(os.Stdout, style, int(canvasWidth), int(canvasHeight))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	cv := markup.Canvas{Width: canvasWidth, Height: canvasHeight}
+	files := flag.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	deck.StartDeck()
+	for _, f := range files {
+		deck.StartSlide(bgcolor, textcolor)
 		data1, data2, err := readData(f)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "%v\n", err)
 			continue
 		}
+		if len(colors) > 0 {
+			recolor(data1, colors)
+			recolor(data2, colors)
+		}
 		if orientation == "tb" {
-			fan(data1, data2, midx, midy, arcsize, canvasWidth, canvasHeight)
+			fan(data1, data2, midx, midy, arcsize, cv)
 		} else {
-			wings(data1, data2, midx, midy, arcsize, canvasWidth, canvasHeight)
+			wings(data1, data2, midx, midy, arcsize, cv)
 		}
-		legend(data1.measures, orientation, labelsize)
-		endSlide()
+		legend(data1.measures, orientation, th.Value.Size, cv)
+		deck.EndSlide()
 	}
-	endDeck()
+	deck.EndDeck()
 }
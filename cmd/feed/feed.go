@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/ajstarks/deck/generate"
+	"github.com/ajstarks/utils/locale"
 )
 
 // Feed has a title, date, with a series of entries
@@ -28,12 +29,13 @@ type entry struct {
 	Link  []string `xml:"link"`
 }
 
-var (
-	outfmt      = flag.String("f", "deck", "output format (deck, rtf, json, html, or plain)")
-	leftmargin  = flag.Float64("left", 15.0, "left margin")
-	rightmargin = flag.Float64("right", 60.0, "right margin")
-	fontsize    = flag.Float64("fs", 1.8, "font size")
-)
+// layout is the placement of deck output
+type layout struct {
+	left, right, fontsize float64
+}
+
+// loc is the locale dates are shown in
+var loc = locale.Default
 
 const (
 	ecolor       = "black"            // entry color
@@ -85,9 +87,19 @@ func xmltranslate(s string) string {
 	return xmlmap.Replace(s)
 }
 
+// showdate shows the feed's date in the locale; dates that
+// cannot be read are shown as they are
+func showdate(date string) string {
+	t, err := time.Parse(dateparsefmt, date)
+	if err != nil {
+		return date
+	}
+	return loc.FormatDate(t)
+}
+
 // genplain outputs the feed markup as plain text
 func genplain(w io.Writer, f Feed) {
-	fmt.Fprintf(w, "%s: %s\n\n", f.Title, f.Date)
+	fmt.Fprintf(w, "%s: %s\n\n", f.Title, showdate(f.Date))
 	for _, e := range f.Entries {
 		fmt.Fprintf(w, "%s\n\n", e.Title)
 		fmt.Fprintf(w, "%s\n", e.Quote)
@@ -128,7 +140,7 @@ func genhtml(w io.Writer, f Feed) {
 	next := t.Add(week)
 	prev := t.Add(-week)
 	fmt.Fprintln(w, htmltop)
-	fmt.Fprintf(w, htmldate, f.Title, prev.Format(datefmt), f.Date, next.Format(datefmt))
+	fmt.Fprintf(w, htmldate, f.Title, prev.Format(datefmt), showdate(f.Date), next.Format(datefmt))
 	for _, e := range f.Entries {
 		fmt.Fprintf(w, htmltitle, e.Title)
 		fmt.Fprintf(w, htmlquote, e.Quote)
@@ -150,18 +162,18 @@ func genjson(w io.Writer, f Feed) {
 }
 
 // gendeck outputs the feed markup as deck markup
-func gendeck(d *generate.Deck, f Feed) {
+func gendeck(d *generate.Deck, f Feed, lay layout) {
 	// set text locations
 	top := 90.0
-	x := *leftmargin
+	x := lay.left
 	y := top
-	fs := *fontsize
-	right := *rightmargin
+	fs := lay.fontsize
+	right := lay.right
 	bottom := 15.0
 
 	d.StartSlide()
 	d.Text(x, y, f.Title, "sans", fs*1.5, tcolor)
-	d.TextEnd(right+x+fs, y, f.Date, "sans", fs, tcolor)
+	d.TextEnd(right+x+fs, y, showdate(f.Date), "sans", fs, tcolor)
 
 	y -= fs * 4.0
 	for _, e := range f.Entries {
@@ -201,7 +213,18 @@ func quoteskip(s string) float64 {
 
 // process each specifed file
 func main() {
+	var lay layout
+	outfmt := flag.String("f", "deck", "output format (deck, rtf, json, html, or plain)")
+	flag.Float64Var(&lay.left, "left", 15.0, "left margin")
+	flag.Float64Var(&lay.right, "right", 60.0, "right margin")
+	flag.Float64Var(&lay.fontsize, "fs", 1.8, "font size")
+	localename := flag.String("locale", "", "locale for dates (as en-US, de-DE or fr)")
 	flag.Parse()
+	var err error
+	if loc, err = locale.Lookup(*localename); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
 	var d *generate.Deck
 	for i, filename := range flag.Args() {
 		f, err := os.Open(filename)
@@ -229,7 +252,7 @@ func main() {
 				d = generate.NewSlides(os.Stdout, 0, 0)
 				d.StartDeck()
 			}
-			gendeck(d, data)
+			gendeck(d, data, lay)
 			if i == len(flag.Args())-1 {
 				d.EndDeck()
 			}
@@ -1,5 +1,10 @@
 module github.com/ajstarks/feed
 
-go 1.16
+go 1.21.6
 
-require github.com/ajstarks/deck/generate v0.0.0-20210223212949-8bd01c798494
+require (
+	github.com/ajstarks/deck/generate v0.0.0-20210223212949-8bd01c798494
+	github.com/ajstarks/utils/locale v0.0.0
+)
+
+replace github.com/ajstarks/utils/locale => ../../locale
@@ -1,3 +1,18 @@
 module github.com/ajstarks/utils/cmd/fox
 
 go 1.21.6
+
+require (
+	github.com/ajstarks/utils/markup v0.0.0
+	github.com/ajstarks/utils/readpalette v0.0.0
+	github.com/ajstarks/utils/scale v0.0.0
+	github.com/ajstarks/utils/seeds v0.0.0
+)
+
+replace github.com/ajstarks/utils/readpalette => ../../readpalette
+
+replace github.com/ajstarks/utils/markup => ../../markup
+
+replace github.com/ajstarks/utils/seeds => ../../seeds
+
+replace github.com/ajstarks/utils/scale => ../../scale
@@ -8,8 +8,19 @@ import (
 	"os"
 	"strconv"
 	"strings"
+
+	"github.com/ajstarks/utils/markup"
+	"github.com/ajstarks/utils/readpalette"
+	"github.com/ajstarks/utils/scale"
+	"github.com/ajstarks/utils/seeds"
 )
 
+// registry holds the built-in palettes and those loaded from a palette file
+var registry = readpalette.Default()
+
+// deck is the markup writer
+var deck markup.Drawer
+
 const minbound = 10
 const maxbound = 95
 const minstep = 2.0
@@ -20,14 +31,12 @@ const defys = -0.5
 const defop = 40
 const rangefmt = "%v,%v,%v"
 
+// rng is the random number source, seeded for each slide
+var rng = rand.New(rand.NewSource(1))
+
 // random returns a random number between a range
 func random(min, max float64) float64 {
-	return vmap(rand.Float64(), 0, 1, min, max)
-}
-
-// vmap maps one interval to another
-func vmap(value, low1, high1, low2, high2 float64) float64 {
-	return low2 + (high2-low2)*(value-low1)/(high1-low1)
+	return scale.NewLinear(0, 1, min, max).Map(rng.Float64())
 }
 
 // parseHues parses a color string: if the string is of the form "h1:h2",
@@ -106,10 +115,10 @@ func triangle(x, y, width, height float64, color string, opacity float64, hue1,
 	if hue1 > -1 && hue2 > -1 { // use hue
 		color = fmt.Sprintf("hsv(%v,100,100)", random(hue1, hue2))
 	}
-	if c, ok := palette[color]; ok { // use a palette
-		color = c[rand.Intn(len(c))]
+	if c, ok := registry.Lookup(color); ok { // use a palette
+		color = c[rng.Intn(len(c))]
 	}
-	fmt.Printf("<polygon xc=\"%v %v %v\" yc=\"%v %v %v\" color=%q opacity=\"%.3f\"/>\n", xp0, xp1, xp2, yp0, yp1, yp2, color, opacity)
+	deck.Polygon([]float64{xp0, xp1, xp2}, []float64{yp0, yp1, yp2}, color, opacity)
 }
 
 // usage prints usage info
@@ -127,25 +136,22 @@ func usage() {
 	fmt.Fprintf(os.Stderr, "-yshift   -0.5                  shadow y shift\n")
 	fmt.Fprintf(os.Stderr, "-bgcolo   white                 background color\n")
 	fmt.Fprintf(os.Stderr, "-p        \"\"                    palette file\n")
+	fmt.Fprintf(os.Stderr, "-style    deck                  output style (deck, decksh, svg)\n")
+	fmt.Fprintf(os.Stderr, "-seed     0                     random seed (0 for a new seed each run)\n")
+	fmt.Fprintf(os.Stderr, "-variations 1                   number of variations, one per slide\n")
 	fmt.Fprintf(os.Stderr, "-color    gray                  color name, hue range (h1:h2), or palette:\n\n")
 	fmt.Fprintln(os.Stderr, "Palette Name                    Colors\n..........................................................")
-	for p, k := range palette {
+	for _, p := range registry.Names() {
+		k, _ := registry.Lookup(p)
 		fmt.Fprintf(os.Stderr, "%-25s\t%v\n", p, k)
 	}
 	os.Exit(1)
 }
 
-// slide generation functions
-func beginDeck()              { fmt.Println("<deck>") }
-func endDeck()                { fmt.Println("</deck>") }
-func beginSlide(color string) { fmt.Printf("<slide bg=%q>\n", color) }
-func endSlide()               { fmt.Println("</slide>") }
-
+// userpalette adds the palettes in a file to the registry
 func userpalette(pfile string) {
 	if len(pfile) > 0 {
-		var err error
-		palette, err = LoadPalette(pfile)
-		if err != nil {
+		if err := registry.LoadFile(pfile); err != nil {
 			fmt.Fprintf(os.Stderr, "%v\n", err)
 			os.Exit(1)
 		}
@@ -160,8 +166,10 @@ func setdir(s string) []string {
 func main() {
 	// options
 	var showhelp bool
-	var bgcolor, color, xconfig, yconfig, pfile, dirs string
+	var bgcolor, color, xconfig, yconfig, pfile, dirs, style string
 	var shadowop, xshift, yshift float64
+	var seed int64
+	var variations int
 	defrange := fmt.Sprintf(rangefmt, minbound, maxbound, defaultstep)
 	flag.BoolVar(&showhelp, "help", false, "show usage")
 	flag.Float64Var(&shadowop, "shadow", 40, "shadow opacity (0 for no shadow shape)")
@@ -172,10 +180,17 @@ func main() {
 	flag.StringVar(&bgcolor, "bgcolor", "white", "background color")
 	flag.StringVar(&dirs, "d", "n s e w sw se nw ne", "directions")
 	flag.StringVar(&pfile, "p", "", "palette file")
+	flag.StringVar(&style, "style", "deck", "output style (deck, decksh, svg)")
+	flag.Int64Var(&seed, "seed", 0, "random seed (0 for a new seed each run)")
+	flag.IntVar(&variations, "variations", 1, "number of variations")
 	flag.StringVar(&color, "color", "gray", "pen color; named color, palette, or h1:h2 for a random hue range hsv(h1:h2, 100, 100)")
 	flag.Parse()
 
 	userpalette(pfile)
+	if len(registry.Resolve(color)) == 0 {
+		fmt.Fprintf(os.Stderr, "%s: palette has no colors\n", color)
+		os.Exit(1)
+	}
 
 	if showhelp {
 		usage()
@@ -188,19 +203,28 @@ func main() {
 	nd := len(directions)
 
 	// generation
-	beginDeck()
-	beginSlide(bgcolor)
-	for y := by; y < ey; y += ystep {
-		for x := bx; x < ex; x += xstep {
-			w := random(minstep, xstep)
-			h := random(minstep, ystep)
-			triangle(x, y, w, h, color, 100, h1, h2, directions[rand.Intn(nd)])
-			if shadowop > 0 {
-				triangle(x+xshift, y+yshift, w, h, color, shadowop, h1, h2, directions[rand.Intn(nd)])
+	var err error
+	if deck, err = markup.New(os.Stdout, style, 0, 0); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	deck.StartDeck()
+	for _, s := range seeds.Int64(seed, variations) {
+		rng = rand.New(rand.NewSource(s))
+		deck.StartSlide(bgcolor)
+		deck.Comment(fmt.Sprintf("fox -seed %d", s))
+		for y := by; y < ey; y += ystep {
+			for x := bx; x < ex; x += xstep {
+				w := random(minstep, xstep)
+				h := random(minstep, ystep)
+				triangle(x, y, w, h, color, 100, h1, h2, directions[rng.Intn(nd)])
+				if shadowop > 0 {
+					triangle(x+xshift, y+yshift, w, h, color, shadowop, h1, h2, directions[rng.Intn(nd)])
+				}
 			}
 		}
+		deck.EndSlide()
 	}
-	endSlide()
-	endDeck()
+	deck.EndDeck()
 
 }
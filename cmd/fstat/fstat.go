@@ -2,11 +2,14 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io/fs"
 	"os"
+	"path/filepath"
 	"sort"
+	"time"
 )
 
 const (
@@ -23,8 +26,22 @@ type dirflags struct {
 	older   bool // date oldest first
 	newer   bool // date newest first
 	showdot bool // show dotfiles
+	json    bool // write JSON Lines
 }
 
+// entry is a file's status, as written with -json
+type entry struct {
+	Path    string `json:"path"`
+	Name    string `json:"name"`
+	Size    int64  `json:"size"`
+	Mode    string `json:"mode"`
+	ModTime string `json:"modtime"`
+	Dir     bool   `json:"dir"`
+	Error   string `json:"error,omitempty"`
+}
+
+var enc = json.NewEncoder(os.Stdout)
+
 // status gets file status
 func status(filename string) (os.FileInfo, bool, error) {
 	f, err := os.Stat(filename)
@@ -108,12 +125,13 @@ func timeSortNewer(files []fs.DirEntry) {
 func dirstat(dirname string, sf dirflags) {
 	f, err := os.Open(dirname)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "%v\n", err)
+		printerr(dirname, err, sf)
 		return
 	}
+	defer f.Close()
 	di, err := f.ReadDir(0)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "%v\n", err)
+		printerr(dirname, err, sf)
 		return
 	}
 	// set the sort option
@@ -140,21 +158,41 @@ func dirstat(dirname string, sf dirflags) {
 	for _, d := range di {
 		fi, err := d.Info()
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "%v\n", err)
+			printerr(filepath.Join(dirname, d.Name()), err, sf)
 			continue
 		}
 		if !sf.showdot && fi.Name()[0] == '.' {
 			continue
 		}
-		printstat(fi)
+		printstat(filepath.Join(dirname, fi.Name()), fi, sf)
 	}
 }
 
 // printstat shows file status
-func printstat(f os.FileInfo) {
+func printstat(path string, f os.FileInfo, sf dirflags) {
+	if sf.json {
+		enc.Encode(entry{
+			Path:    path,
+			Name:    f.Name(),
+			Size:    f.Size(),
+			Mode:    f.Mode().String(),
+			ModTime: f.ModTime().Format(time.RFC3339),
+			Dir:     f.IsDir(),
+		})
+		return
+	}
 	fmt.Printf(dirfmt, f.Mode(), f.ModTime().Format(timefmt), f.Size(), f.Name())
 }
 
+// printerr shows an error reading a file's status
+func printerr(path string, err error, sf dirflags) {
+	if sf.json {
+		enc.Encode(entry{Path: path, Error: err.Error()})
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%v\n", err)
+}
+
 func main() {
 	var df dirflags
 	flag.BoolVar(&df.showdot, "a", false, "show dot files")
@@ -164,6 +202,7 @@ func main() {
 	flag.BoolVar(&df.sd, "sd", false, "sort by size descending")
 	flag.BoolVar(&df.older, "old", false, "sort by age oldest first")
 	flag.BoolVar(&df.newer, "new", false, "sort by age newest first")
+	flag.BoolVar(&df.json, "json", false, "write JSON Lines")
 	flag.Parse()
 	args := flag.Args()
 
@@ -193,16 +232,16 @@ func main() {
 	for i, filename := range args {
 		s, isdir, err := status(filename)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "%v\n", err)
+			printerr(filename, err, df)
 			continue
 		}
 		if isdir {
-			if i > 0 {
+			if i > 0 && !df.json {
 				fmt.Printf("%s:\n", filename)
 			}
 			dirstat(filename, df)
 		} else {
-			printstat(s)
+			printstat(filename, s, df)
 		}
 	}
 }
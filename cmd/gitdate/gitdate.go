@@ -1,4 +1,4 @@
-// gitdate: visualize git commit history
+// gitdate: visualize git commit history on a time axis
 // git log --date iso | awk '/^Date:/ {print $2, $3, $4}' | gitdate ... | decksh | ...
 package main
 
@@ -9,6 +9,12 @@ import (
 	"io"
 	"os"
 	"time"
+
+	"github.com/ajstarks/utils/locale"
+	"github.com/ajstarks/utils/scale"
+	"github.com/ajstarks/utils/settings"
+	"github.com/ajstarks/utils/source"
+	"github.com/ajstarks/utils/theme"
 )
 
 const (
@@ -20,64 +26,133 @@ type config struct {
 	title, btime, etime, color           string
 	left, right, radius, ypoint, opacity float64
 	fulldeck                             bool
+	theme                                theme.Theme
+	locale                               locale.Locale
 }
 
-// seconds returns the number of seconds of the specified time
-// since the Unix epoch (Jan 1, 1970, 00:00:00 UTC)
-func seconds(s string) int64 {
-	t, err := time.Parse(gitime, s)
-	if err != nil {
-		return -1
+// ticks is the number of axis ticks to aim for
+const ticks = 6
+
+// defaults is the look without a theme: commits are the first
+// accent color, and the tick marks are grid lines
+var defaults = theme.Theme{
+	Accent: []string{"black"},
+	Title:  theme.Font{Size: 2},
+	Label:  theme.Font{Size: 1},
+}
+
+// textopts formats the size and font of decksh text
+func textopts(f theme.Font) string {
+	if len(f.Name) > 0 {
+		return fmt.Sprintf("%v %q", f.Size, f.Name)
 	}
-	return t.Unix()
+	return fmt.Sprintf("%v", f.Size)
 }
 
-//vmap maps one interval to another
-func vmap(value float64, low1 float64, high1 float64, low2 float64, high2 float64) float64 {
-	return low2 + (high2-low2)*(value-low1)/(high1-low1)
+// lineopts formats the width and color of a decksh line
+func lineopts(width float64, color string) string {
+	if len(color) > 0 {
+		return fmt.Sprintf("%v %q", width, color)
+	}
+	return fmt.Sprintf("%v", width)
+}
+
+// readtimes reads timestamps in the ("2006-01-02 15:04:05 -0700") format,
+// one per line, skipping those that cannot be parsed
+func readtimes(r io.Reader) ([]time.Time, error) {
+	var times []time.Time
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		t, err := time.Parse(gitime, scanner.Text())
+		if err != nil {
+			continue
+		}
+		times = append(times, t)
+	}
+	return times, scanner.Err()
+}
+
+// timescale makes the time scale from the begin and end options;
+// if they are not set, the range of the data, rounded out, is used
+func timescale(times []time.Time, c config) (*scale.Time, error) {
+	var b, e time.Time
+	for i, t := range times {
+		if i == 0 || t.Before(b) {
+			b = t
+		}
+		if i == 0 || t.After(e) {
+			e = t
+		}
+	}
+	var err error
+	if len(c.btime) > 0 {
+		if b, err = time.Parse(isotime, c.btime); err != nil {
+			return nil, err
+		}
+	}
+	if len(c.etime) > 0 {
+		if e, err = time.Parse(isotime, c.etime); err != nil {
+			return nil, err
+		}
+	}
+	if !e.After(b) {
+		e = b.Add(time.Hour)
+	}
+	s := scale.NewTime(b, e, c.left, c.right)
+	if len(c.btime) == 0 || len(c.etime) == 0 {
+		lo, hi := s.Domain[0], s.Domain[1]
+		s.Nice(ticks)
+		if len(c.btime) > 0 {
+			s.Domain[0] = lo
+		}
+		if len(c.etime) > 0 {
+			s.Domain[1] = hi
+		}
+	}
+	return s, nil
 }
 
 // process reads a series of line containing timestamps
-// in the ("Mon Jan 2 15:04:05 2006 -0700") format
-// and maps each time time to a linear scale.
+// in the ("2006-01-02 15:04:05 -0700") format
+// and maps each time to a labeled time axis.
 func process(w io.Writer, r io.Reader, c config) error {
-	b, err := time.Parse(isotime, c.btime)
+	times, err := readtimes(r)
 	if err != nil {
 		return err
 	}
-	e, err := time.Parse(isotime, c.etime)
+	ts, err := timescale(times, c)
 	if err != nil {
 		return err
 	}
-	beg := b.Unix()
-	end := e.Unix()
 
 	labely := c.ypoint + 5
 	if c.fulldeck {
-		fmt.Fprintln(w, "deck\nslide")
+		fmt.Fprint(w, "deck\nslide")
+		for _, color := range c.theme.SlideColors() {
+			fmt.Fprintf(w, " %q", color)
+		}
+		fmt.Fprintln(w)
 	}
-	fmt.Fprintf(w, "ctext %q %v %v %v\n", c.btime, c.left, labely, 1)
-	fmt.Fprintf(w, "ctext %q %v %v %v\n", c.etime, c.right, labely, 1)
-	fmt.Fprintf(w, "ctext %q %v %v 2\n", c.title, c.left+((c.right-c.left)/2), labely)
-	fmt.Fprintf(w, "vline %v %v %v 0.1\n", c.left, c.ypoint, 4)
-	fmt.Fprintf(w, "vline %v %v %v 0.1\n", c.right, c.ypoint, 4)
-	scanner := bufio.NewScanner(r)
-	for scanner.Scan() {
-		t := scanner.Text()
-		s := seconds(t)
-		x := vmap(float64(s), float64(beg), float64(end), c.left, c.right)
-		fmt.Fprintf(w, "circle %v %v %v %q %v\n", x, c.ypoint, c.radius, c.color, c.opacity)
+	fmt.Fprintf(w, "ctext %q %v %v %s\n", c.title, c.left+((c.right-c.left)/2), labely+3, textopts(c.theme.Title))
+	for _, t := range ts.Ticks(ticks) {
+		x := ts.Map(t.Time)
+		fmt.Fprintf(w, "ctext %q %.2f %v %s\n", c.locale.FormatTime(t.Time, t.Layout), x, labely, textopts(c.theme.Label))
+		fmt.Fprintf(w, "vline %.2f %v %v %s\n", x, c.ypoint, 4, lineopts(0.1, c.theme.Grid))
+	}
+	for _, t := range times {
+		x := ts.Map(t)
+		fmt.Fprintf(w, "circle %.2f %v %v %q %v\n", x, c.ypoint, c.radius, c.color, c.opacity)
 	}
 	if c.fulldeck {
 		fmt.Fprintln(w, "eslide\nedeck")
 	}
-	return scanner.Err()
+	return nil
 }
 
 func main() {
 	title := flag.String("title", "commit history", "title")
-	btime := flag.String("begin", "1970-01-01T00:00:00+00:00", "begin time")
-	etime := flag.String("end", time.Now().Format(isotime), "end time")
+	btime := flag.String("begin", "", "begin time (default: the first commit)")
+	etime := flag.String("end", "", "end time (default: the last commit)")
 	ypoint := flag.Float64("y", 50, "y point")
 	radius := flag.Float64("r", 2, "radius")
 	color := flag.String("color", "black", "color")
@@ -85,7 +160,22 @@ func main() {
 	right := flag.Float64("right", 90, "right")
 	opacity := flag.Float64("opacity", 20, "opacity")
 	fulldeck := flag.Bool("fulldeck", true, "full deck markup")
-	flag.Parse()
+	themename := flag.String("theme", "", "theme (light, dark, print, or a JSON file)")
+	localename := flag.String("locale", "", "locale for month and day names (as en-US, de-DE or fr)")
+	source.Flags()
+	settings.Parse()
+
+	th, err := theme.Choose(*themename, defaults)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	loc, err := locale.Lookup(*localename)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	theme.Use(color, "color", th.Color(0))
 
 	c := config{
 		title:    *title,
@@ -98,9 +188,17 @@ func main() {
 		left:     *left,
 		right:    *right,
 		fulldeck: *fulldeck,
+		theme:    th,
+		locale:   loc,
 	}
 
-	err := process(os.Stdout, os.Stdin, c)
+	r, err := source.Open(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	defer r.Close()
+	err = process(os.Stdout, r, c)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
@@ -1,3 +1,7 @@
 module github.com/ajstarks/utils/cmd/gurl
 
-go 1.14
+go 1.21.6
+
+require github.com/ajstarks/utils/source v0.0.0
+
+replace github.com/ajstarks/utils/source => ../../source
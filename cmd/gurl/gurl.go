@@ -5,29 +5,21 @@ import (
 	"flag"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"time"
-)
 
-// netread derefernces a URL, returning the Reader, with an error
-func netread(url string, timeout int) (io.ReadCloser, error) {
-	client := &http.Client{Timeout: time.Duration(timeout) * time.Second}
-	resp, err := client.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unable to get network data for %s (%s)", url, resp.Status)
-	}
-	return resp.Body, nil
-}
+	"github.com/ajstarks/utils/source"
+)
 
 func main() {
 	timeout := flag.Int("timeout", 30, "time out (sec)")
+	maxage := flag.Duration("maxage", 0, "use URLs cached for up to this long (0 for no cache)")
 	flag.Parse()
+	opener := source.Default
+	opener.Timeout = time.Duration(*timeout) * time.Second
+	opener.MaxAge = *maxage
 	for _, url := range flag.Args() {
-		r, err := netread(url, *timeout)
+		r, err := opener.Open(url)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "%v\n", err)
 			continue
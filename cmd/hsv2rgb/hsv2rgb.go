@@ -2,35 +2,83 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"math"
+	"os"
 	"strconv"
 	"strings"
 )
 
+// color is an hsv color and its rgb equivalent, as written with -json
+type color struct {
+	Hue        float64 `json:"hue"`
+	Saturation float64 `json:"saturation"`
+	Value      float64 `json:"value"`
+	Red        int     `json:"red"`
+	Green      int     `json:"green"`
+	Blue       int     `json:"blue"`
+	Error      string  `json:"error,omitempty"`
+}
+
 func main() {
 	var hue, saturation, value float64
 	var name string
+	var jsonout bool
 	flag.Float64Var(&hue, "h", 360, "hue")
 	flag.Float64Var(&saturation, "s", 50, "saturation")
 	flag.Float64Var(&value, "v", 50, "value")
 	flag.StringVar(&name, "n", "", "named color like 'hsv(0,20,50)'")
+	flag.BoolVar(&jsonout, "json", false, "write JSON")
 	flag.Parse()
 
 	var r, g, b int
+	var err error
 	if len(name) > 0 {
-		v := colorNumbers(name)
-		if len(v) == 3 {
-			hue, _ = strconv.ParseFloat(v[0], 64)
-			saturation, _ = strconv.ParseFloat(v[1], 64)
-			value, _ = strconv.ParseFloat(v[2], 64)
-			r, g, b = hsv2rgb(hue, saturation, value)
+		var h, s, v float64
+		if h, s, v, err = parsehsv(name); err == nil {
+			hue, saturation, value = h, s, v
 		}
-	} else {
+	}
+	if err == nil {
 		r, g, b = hsv2rgb(hue, saturation, value)
 	}
-	fmt.Printf("hsv(%g, %g, %g) => rgb(%d, %d, %d)\n", hue, saturation, value, r, g, b)
+	if jsonout {
+		c := color{Hue: hue, Saturation: saturation, Value: value, Red: r, Green: g, Blue: b}
+		if err != nil {
+			c.Error = err.Error()
+		}
+		json.NewEncoder(os.Stdout).Encode(c)
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+	} else {
+		fmt.Printf("hsv(%g, %g, %g) => rgb(%d, %d, %d)\n", hue, saturation, value, r, g, b)
+	}
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+// parsehsv reads the hue, saturation and value of a color like 'hsv(0,20,50)'
+func parsehsv(name string) (float64, float64, float64, error) {
+	bad := fmt.Errorf("%q is not a color like 'hsv(0,20,50)'", name)
+	if !strings.HasPrefix(name, "hsv(") || !strings.HasSuffix(name, ")") {
+		return 0, 0, 0, bad
+	}
+	v := colorNumbers(name)
+	if len(v) != 3 {
+		return 0, 0, 0, bad
+	}
+	var hsv [3]float64
+	for i, s := range v {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, 0, 0, bad
+		}
+		hsv[i] = f
+	}
+	return hsv[0], hsv[1], hsv[2], nil
 }
 
 // colorNumbers returns a list of numbers from a comma separated list,
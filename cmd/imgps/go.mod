@@ -1,5 +1,12 @@
 module github.com/ajstarks/utils/cmd/imgps
 
-go 1.21.5
+go 1.21.6
 
-require github.com/rwcarlsen/goexif v0.0.0-20190401172101-9e8deecbddbd
+require (
+	github.com/ajstarks/utils/imagescan v0.0.0
+	github.com/rwcarlsen/goexif v0.0.0-20190401172101-9e8deecbddbd
+)
+
+require golang.org/x/image v0.18.0 // indirect
+
+replace github.com/ajstarks/utils/imagescan => ../../imagescan
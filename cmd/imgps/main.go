@@ -2,43 +2,67 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
 	"os"
 
+	"github.com/ajstarks/utils/imagescan"
 	"github.com/rwcarlsen/goexif/exif"
 )
 
+// location is a file's GPS coordinates, as written with -json
+type location struct {
+	File      string  `json:"file"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Error     string  `json:"error,omitempty"`
+
+	err error
+}
+
 // for every file on the command line, report GPS coordinates
 func main() {
-	for _, f := range os.Args[1:] {
-		err := process(f)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "%s: %v\n", f, err)
-			continue
+	jsonout := flag.Bool("json", false, "write JSON Lines")
+	imagescan.Flags()
+	flag.Parse()
+	enc := json.NewEncoder(os.Stdout)
+	files := imagescan.Default.Files(flag.Args())
+	imagescan.Map(imagescan.Default, files, process, func(loc location) {
+		if *jsonout {
+			if loc.err != nil {
+				loc.Error = loc.err.Error()
+			}
+			enc.Encode(loc)
+			return
 		}
-	}
+		if loc.err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", loc.File, loc.err)
+			return
+		}
+		fmt.Printf("%s %.8f %.8f\n", loc.File, loc.Latitude, loc.Longitude)
+	})
 }
 
 // process retrieves GPS coordinates from a file
-func process(filename string) error {
+func process(filename string) location {
+	loc := location{File: filename}
 	f, err := os.Open(filename)
 	if err != nil {
-		return err
+		loc.err = err
+		return loc
 	}
 	defer f.Close()
 	x, err := exif.Decode(f)
 	if err == io.EOF {
-		return fmt.Errorf("no exif data")
-	}
-	if err != nil {
-		return err
+		loc.err = fmt.Errorf("no exif data")
+		return loc
 	}
-	lat, long, err := x.LatLong()
 	if err != nil {
-		return err
+		loc.err = err
+		return loc
 	}
-	fmt.Printf("%s %.8f %.8f\n", filename, lat, long)
-
-	return nil
+	loc.Latitude, loc.Longitude, loc.err = x.LatLong()
+	return loc
 }
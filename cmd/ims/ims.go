@@ -1,28 +1,41 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
 	"fmt"
-	"image"
-	_ "image/gif"
-	_ "image/jpeg"
-	_ "image/png"
 	"os"
+
+	"github.com/ajstarks/utils/imagescan"
 )
 
+// imsize is an image's size, as written with -json
+type imsize struct {
+	File   string `json:"file"`
+	Format string `json:"format"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Error  string `json:"error,omitempty"`
+}
+
 func main() {
-	for _, fname := range os.Args[1:] {
-		f, ferr := os.Open(fname)
-		if ferr != nil {
-			fmt.Fprintf(os.Stderr, "%v\n", ferr)
-			continue
+	jsonout := flag.Bool("json", false, "write JSON Lines")
+	imagescan.Flags()
+	flag.Parse()
+	enc := json.NewEncoder(os.Stdout)
+	imagescan.Scan(flag.Args(), func(im imagescan.Image) {
+		if *jsonout {
+			s := imsize{File: im.Name, Format: im.Format, Width: im.Width, Height: im.Height}
+			if im.Err != nil {
+				s.Error = im.Err.Error()
+			}
+			enc.Encode(s)
+			return
 		}
-		im, _, err := image.DecodeConfig(f)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "%s: %v\n", fname, err)
-			continue
+		if im.Err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", im.Err)
+			return
 		}
-		fmt.Printf("%s %d %d\n", fname, im.Width, im.Height)
-		f.Close()
-
-	}
+		fmt.Printf("%s %d %d\n", im.Name, im.Width, im.Height)
+	})
 }
@@ -1,5 +1,10 @@
 module github.com/ajstarks/utils/cmd/latlongdeck
 
-go 1.21.5
+go 1.21.6
 
-require github.com/ajstarks/kml v0.0.0-20231216032752-dd72e94de437
+require (
+	github.com/ajstarks/kml v0.0.0-20231216032752-dd72e94de437
+	github.com/ajstarks/utils/scale v0.0.0
+)
+
+replace github.com/ajstarks/utils/scale => ../../scale
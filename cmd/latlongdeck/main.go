@@ -10,17 +10,15 @@ import (
 	"strings"
 
 	"github.com/ajstarks/kml"
+	"github.com/ajstarks/utils/scale"
 )
 
-// vmap maps one interval to another
-func vmap(value float64, low1 float64, high1 float64, low2 float64, high2 float64) float64 {
-	return low2 + (high2-low2)*(value-low1)/(high1-low1)
-}
-
 // readData reads lat/long pairs (separated by white space) from a file, mapping to deck coordinates
 func readData(r io.Reader, g kml.Geometry) ([]float64, []float64, error) {
 	x := []float64{}
 	y := []float64{}
+	xs := scale.NewLinear(g.Longmin, g.Longmax, g.Xmin, g.Xmax)
+	ys := scale.NewLinear(g.Latmin, g.Latmax, g.Ymin, g.Ymax)
 	s := bufio.NewScanner(r)
 	for s.Scan() {
 		t := s.Text()
@@ -36,8 +34,8 @@ func readData(r io.Reader, g kml.Geometry) ([]float64, []float64, error) {
 		if err != nil {
 			continue
 		}
-		x = append(x, vmap(xp, g.Longmin, g.Longmax, g.Xmin, g.Xmax))
-		y = append(y, vmap(yp, g.Latmin, g.Latmax, g.Ymin, g.Ymax))
+		x = append(x, xs.Map(xp))
+		y = append(y, ys.Map(yp))
 	}
 	return x, y, s.Err()
 }
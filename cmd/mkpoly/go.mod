@@ -1,3 +1,7 @@
 module github.com/ajstarks/mkpoly
 
-go 1.16
+go 1.21.6
+
+require github.com/ajstarks/utils/scale v0.0.0
+
+replace github.com/ajstarks/utils/scale => ../../scale
@@ -10,6 +10,8 @@ import (
 	"os"
 	"strconv"
 	"strings"
+
+	"github.com/ajstarks/utils/scale"
 )
 
 const (
@@ -90,11 +92,13 @@ func deck(p params, filename string) error {
 		return err
 	}
 
+	xs := scale.NewLinear(p.minx, p.maxx, p.left, p.right)
+	ys := scale.NewLinear(p.miny, p.maxy, p.bottom, p.top)
 	pminx := largest
 	pmaxx := smallest
 	fmt.Printf("<polygon xc=\"")
 	for i := 0; i < len(x); i++ {
-		px := vmap(x[i], p.minx, p.maxx, p.left, p.right)
+		px := xs.Map(x[i])
 		if px > pmaxx {
 			pmaxx = px
 		}
@@ -103,13 +107,13 @@ func deck(p params, filename string) error {
 		}
 		fmt.Printf("%.3g ", px)
 	}
-	fmt.Printf("%.3g\"", vmap(x[0], p.minx, p.maxx, p.left, p.right))
+	fmt.Printf("%.3g\"", xs.Map(x[0]))
 
 	pminy := largest
 	pmaxy := smallest
 	fmt.Printf("  yc=\"")
 	for i := 0; i < len(y); i++ {
-		py := vmap(y[i], p.miny, p.maxy, p.bottom, p.top)
+		py := ys.Map(y[i])
 		if py > pmaxy {
 			pmaxy = py
 		}
@@ -118,9 +122,9 @@ func deck(p params, filename string) error {
 		}
 		fmt.Printf("%.3g ", py)
 	}
-	fmt.Printf("%.3g\" color=\"%s\"/>\n", vmap(y[0], p.miny, p.maxy, p.bottom, p.top), p.color)
+	fmt.Printf("%.3g\" color=\"%s\"/>\n", ys.Map(y[0]), p.color)
 	if len(p.label) > 0 {
-		fmt.Printf("<text align=\"c\" xp=\"%g\" yp=\"%g\" sp=\"1\"/>%s/>\n", pminx+((pmaxx-pminx)/2), pminy+((pmaxy-pminy)/2), p.label)
+		fmt.Printf("<text align=\"c\" xp=\"%g\" yp=\"%g\" sp=\"1\">%s</text>\n", pminx+((pmaxx-pminx)/2), pminy+((pmaxy-pminy)/2), p.label)
 	}
 	return r.Close()
 }
@@ -139,11 +143,13 @@ func decksh(p params, filename string) error {
 		return err
 	}
 
+	xs := scale.NewLinear(p.minx, p.maxx, p.left, p.right)
+	ys := scale.NewLinear(p.miny, p.maxy, p.bottom, p.top)
 	pminx := largest
 	pmaxx := smallest
 	fmt.Printf("polygon \"")
 	for i := 0; i < len(x); i++ {
-		px := vmap(x[i], p.minx, p.maxx, p.left, p.right)
+		px := xs.Map(x[i])
 		if px > pmaxx {
 			pmaxx = px
 		}
@@ -152,13 +158,13 @@ func decksh(p params, filename string) error {
 		}
 		fmt.Printf("%.3g ", px)
 	}
-	fmt.Printf("%.3g\"", vmap(x[0], p.minx, p.maxx, p.left, p.right))
+	fmt.Printf("%.3g\"", xs.Map(x[0]))
 
 	pminy := largest
 	pmaxy := smallest
 	fmt.Printf("  \"")
 	for i := 0; i < len(y); i++ {
-		py := vmap(y[i], p.miny, p.maxy, p.bottom, p.top)
+		py := ys.Map(y[i])
 		if py > pmaxy {
 			pmaxy = py
 		}
@@ -167,14 +173,9 @@ func decksh(p params, filename string) error {
 		}
 		fmt.Printf("%.3g ", py)
 	}
-	fmt.Printf("%.3g\" \"%s\"\n", vmap(y[0], p.miny, p.maxy, p.bottom, p.top), p.color)
+	fmt.Printf("%.3g\" \"%s\"\n", ys.Map(y[0]), p.color)
 	if len(p.label) > 0 {
 		fmt.Printf("ctext \"%s\" %g %g 1\n", p.label, pminx+((pmaxx-pminx)/2), pminy+((pmaxy-pminy)/2))
 	}
 	return r.Close()
 }
-
-// vmap maps one range to another
-func vmap(value, low1, high1, low2, high2 float64) float64 {
-	return low2 + (high2-low2)*(value-low1)/(high1-low1)
-}
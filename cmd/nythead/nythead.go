@@ -4,10 +4,9 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
-	"time"
+
+	"github.com/ajstarks/utils/source"
 )
 
 // API Info
@@ -47,19 +46,6 @@ func apikey(s string) string {
 	return key
 }
 
-// netread derefernces a URL, returning the Reader, with an error
-func netread(url string) (io.ReadCloser, error) {
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unable to get network data for %s (%s)", url, resp.Status)
-	}
-	return resp.Body, nil
-}
-
 // nytheadlines retrieves data from the New York Times API, decodes and displays it.
 func nytheadlines(section string) {
 	key := apikey(NYTAPIkey)
@@ -67,7 +53,7 @@ func nytheadlines(section string) {
 		fmt.Fprintln(os.Stderr, "invalid API key")
 		return
 	}
-	r, err := netread(fmt.Sprintf(NYTfmt, section, key))
+	r, err := source.Open(fmt.Sprintf(NYTfmt, section, key))
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "headline read error: %v\n", err)
 		return
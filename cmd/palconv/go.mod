@@ -0,0 +1,7 @@
+module github.com/ajstarks/utils/cmd/palconv
+
+go 1.21.6
+
+require github.com/ajstarks/utils/readpalette v0.0.0
+
+replace github.com/ajstarks/utils/readpalette => ../../readpalette
@@ -0,0 +1,200 @@
+// palconv -- convert palette files between formats
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ajstarks/utils/readpalette"
+)
+
+// readable lists the extensions of palette files found in directories
+var readable = map[string]bool{".pal": true, ".gpl": true, ".ase": true, ".hex": true, ".json": true}
+
+// expand returns the palette files named by the arguments;
+// directories are replaced by the palette files they contain
+func expand(args []string) ([]string, error) {
+	var files []string
+	for _, a := range args {
+		fi, err := os.Stat(a)
+		if err != nil {
+			return nil, err
+		}
+		if !fi.IsDir() {
+			files = append(files, a)
+			continue
+		}
+		entries, err := os.ReadDir(a)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if !e.IsDir() && readable[strings.ToLower(filepath.Ext(e.Name()))] {
+				files = append(files, filepath.Join(a, e.Name()))
+			}
+		}
+	}
+	return files, nil
+}
+
+// strict makes conversion fail on colors that cannot be parsed,
+// or that would lose their alpha
+var strict bool
+
+// load reads a palette file, using the from format if specified
+func load(filename, from string) (map[string][]string, error) {
+	p, err := read(filename, from)
+	if err != nil || !strict {
+		return p, err
+	}
+	if _, err := readpalette.ToRGBStrict(p); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return p, nil
+}
+
+func read(filename, from string) (map[string][]string, error) {
+	if len(from) == 0 {
+		return readpalette.LoadPalette(filename)
+	}
+	f, err := readpalette.ParseFormat(from)
+	if err != nil {
+		return nil, err
+	}
+	r, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	base := filepath.Base(filename)
+	return readpalette.Read(r, f, strings.TrimSuffix(base, filepath.Ext(base)))
+}
+
+// flatten drops the alpha of translucent colors written in formats without
+// it, warning of each; with -strict, writing them is an error instead
+func flatten(p map[string][]string, f readpalette.Format) map[string][]string {
+	if strict || f.Alpha() {
+		return p
+	}
+	flat := make(map[string][]string, len(p))
+	for name, colors := range p {
+		fc := make([]string, len(colors))
+		for i, c := range colors {
+			fc[i] = c
+			if v, err := readpalette.ParseColor(c); err == nil && v.A != 0xff {
+				fc[i] = fmt.Sprintf("#%02x%02x%02x", v.R, v.G, v.B)
+				fmt.Fprintf(os.Stderr, "%s: %s loses its alpha in %s\n", name, c, f)
+			}
+		}
+		flat[name] = fc
+	}
+	return flat
+}
+
+// create writes a palette to the named file
+func create(filename string, p map[string][]string, f readpalette.Format) error {
+	w, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := readpalette.Write(w, flatten(p, f), f); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// convert writes the palettes in a file to the output directory,
+// one output file per input file, or one per palette for single palette formats.
+func convert(filename, outdir, from string, f readpalette.Format) error {
+	p, err := load(filename, from)
+	if err != nil {
+		return err
+	}
+	if !f.Single() {
+		base := filepath.Base(filename)
+		return create(filepath.Join(outdir, strings.TrimSuffix(base, filepath.Ext(base))+f.Extension()), p, f)
+	}
+	for name, colors := range p {
+		if err := create(filepath.Join(outdir, name+f.Extension()), map[string][]string{name: colors}, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// merge reads all files into a single palette map and writes it
+func merge(w io.Writer, files []string, from string, f readpalette.Format) error {
+	all := make(map[string][]string)
+	for _, filename := range files {
+		p, err := load(filename, from)
+		if err != nil {
+			return err
+		}
+		for name, colors := range p {
+			all[name] = colors
+		}
+	}
+	return readpalette.Write(w, flatten(all, f), f)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: palconv [options] file|dir...")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintf(os.Stderr, "Option    Default    Description\n")
+	fmt.Fprintf(os.Stderr, ".....................................................\n")
+	fmt.Fprintf(os.Stderr, "-to       native     output format (native, gpl, ase, hex, json, css, deck)\n")
+	fmt.Fprintf(os.Stderr, "-from     \"\"         input format (default: detect)\n")
+	fmt.Fprintf(os.Stderr, "-d        \"\"         output directory (default: merge to standard output)\n")
+	fmt.Fprintf(os.Stderr, "-strict   false      fail on colors that cannot be parsed, or would lose alpha\n")
+}
+
+func main() {
+	var to, from, outdir string
+	flag.StringVar(&to, "to", "native", "output format")
+	flag.StringVar(&from, "from", "", "input format")
+	flag.StringVar(&outdir, "d", "", "output directory")
+	flag.BoolVar(&strict, "strict", false, "fail on colors that cannot be parsed, or would lose alpha")
+	flag.Usage = usage
+	flag.Parse()
+
+	f, err := readpalette.ParseFormat(to)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	args := flag.Args()
+	if len(args) == 0 {
+		usage()
+		os.Exit(1)
+	}
+	files, err := expand(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	if len(outdir) == 0 {
+		if err := merge(os.Stdout, files, from, f); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if err := os.MkdirAll(outdir, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	status := 0
+	for _, filename := range files {
+		if err := convert(filename, outdir, from, f); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", filename, err)
+			status = 1
+		}
+	}
+	os.Exit(status)
+}
@@ -0,0 +1,219 @@
+// palettize -- quantize images to a palette, with optional dithering
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"math"
+	"os"
+
+	"github.com/ajstarks/utils/readpalette"
+)
+
+// diffusion is an error diffusion kernel: offsets and weights
+type diffusion struct {
+	dx, dy int
+	weight float64
+}
+
+var kernels = map[string][]diffusion{
+	"fs": {
+		{1, 0, 7.0 / 16}, {-1, 1, 3.0 / 16}, {0, 1, 5.0 / 16}, {1, 1, 1.0 / 16},
+	},
+	"atkinson": {
+		{1, 0, 1.0 / 8}, {2, 0, 1.0 / 8}, {-1, 1, 1.0 / 8}, {0, 1, 1.0 / 8}, {1, 1, 1.0 / 8}, {0, 2, 1.0 / 8},
+	},
+}
+
+// bayer returns the n x n ordered dither threshold matrix (n a power of two)
+func bayer(n int) [][]float64 {
+	m := [][]float64{{0}}
+	for size := 1; size < n; size *= 2 {
+		next := make([][]float64, size*2)
+		for y := range next {
+			next[y] = make([]float64, size*2)
+		}
+		for y := 0; y < size; y++ {
+			for x := 0; x < size; x++ {
+				v := 4 * m[y][x]
+				next[y][x] = v
+				next[y][x+size] = v + 2
+				next[y+size][x] = v + 3
+				next[y+size][x+size] = v + 1
+			}
+		}
+		m = next
+	}
+	// normalize to -0.5..0.5
+	for y := range m {
+		for x := range m[y] {
+			m[y][x] = (m[y][x]+0.5)/float64(n*n) - 0.5
+		}
+	}
+	return m
+}
+
+// clamp limits a value to 0..255
+func clamp(v float64) uint8 {
+	return uint8(math.Max(0, math.Min(255, math.Round(v))))
+}
+
+// pixels returns the image as rows of r, g, b values
+func pixels(img image.Image) [][][3]float64 {
+	b := img.Bounds()
+	rows := make([][][3]float64, b.Dy())
+	for y := range rows {
+		rows[y] = make([][3]float64, b.Dx())
+		for x := range rows[y] {
+			c := color.NRGBAModel.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.NRGBA)
+			rows[y][x] = [3]float64{float64(c.R), float64(c.G), float64(c.B)}
+		}
+	}
+	return rows
+}
+
+// palettize maps every pixel to the nearest palette color, diffusing
+// the error with the named kernel or applying an ordered Bayer matrix
+func palettize(img image.Image, colors []color.NRGBA, dither string, bsize int) (*image.Paletted, error) {
+	pal := make(color.Palette, len(colors))
+	for i, c := range colors {
+		pal[i] = c
+	}
+	m := readpalette.NewMatcher(colors)
+	px := pixels(img)
+	h := len(px)
+	w := 0
+	if h > 0 {
+		w = len(px[0])
+	}
+	out := image.NewPaletted(image.Rect(0, 0, w, h), pal)
+
+	var threshold [][]float64
+	kernel, diffuse := kernels[dither]
+	switch {
+	case dither == "bayer":
+		if bsize < 2 || bsize&(bsize-1) != 0 {
+			return nil, fmt.Errorf("bayer size must be a power of two, got %d", bsize)
+		}
+		threshold = bayer(bsize)
+	case dither != "none" && !diffuse:
+		return nil, fmt.Errorf("unknown dither %q", dither)
+	}
+	spread := 255 / math.Max(1, math.Cbrt(float64(len(colors))))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := px[y][x]
+			if threshold != nil {
+				t := threshold[y%bsize][x%bsize] * spread
+				v = [3]float64{v[0] + t, v[1] + t, v[2] + t}
+			}
+			v = [3]float64{float64(clamp(v[0])), float64(clamp(v[1])), float64(clamp(v[2]))}
+			i := m.Index(color.NRGBA{R: uint8(v[0]), G: uint8(v[1]), B: uint8(v[2]), A: 0xff})
+			out.SetColorIndex(x, y, uint8(i))
+			if !diffuse {
+				continue
+			}
+			c := colors[i]
+			e := [3]float64{v[0] - float64(c.R), v[1] - float64(c.G), v[2] - float64(c.B)}
+			for _, k := range kernel {
+				nx, ny := x+k.dx, y+k.dy
+				if nx < 0 || nx >= w || ny >= h {
+					continue
+				}
+				for j := range e {
+					px[ny][nx][j] += e[j] * k.weight
+				}
+			}
+		}
+	}
+	return out, nil
+}
+
+// process reads an image, palettizes it, and writes a PNG
+func process(w io.Writer, r io.Reader, colors []color.NRGBA, dither string, bsize int) error {
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return err
+	}
+	out, err := palettize(img, colors, dither, bsize)
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, out)
+}
+
+// palette resolves a palette name through the registry, after adding the
+// palettes from a file; with no name, the first palette in the file is used
+func palette(pfile, name string) ([]color.NRGBA, error) {
+	registry := readpalette.Default()
+	if len(pfile) > 0 {
+		p, err := readpalette.LoadPalette(pfile)
+		if err != nil {
+			return nil, err
+		}
+		registry.Merge(p, "user")
+		if len(name) == 0 {
+			name = readpalette.Names(p)[0]
+		}
+	}
+	colors := readpalette.ToRGB(map[string][]string{name: registry.Resolve(name)})[name]
+	if len(colors) == 0 {
+		return nil, fmt.Errorf("no palette named %q", name)
+	}
+	if len(colors) > 256 {
+		return nil, fmt.Errorf("%s: palette has %d colors, limit is 256", name, len(colors))
+	}
+	return colors, nil
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: palettize [-p palette-file] [-color palette] [options] [image] > out.png")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintf(os.Stderr, "Option    Default    Description\n")
+	fmt.Fprintf(os.Stderr, ".....................................................\n")
+	fmt.Fprintf(os.Stderr, "-p        \"\"         palette file, added to the built-in palettes\n")
+	fmt.Fprintf(os.Stderr, "-color    \"\"         palette name (default: first in the palette file)\n")
+	fmt.Fprintf(os.Stderr, "-dither   none       dithering (none, fs, atkinson, bayer)\n")
+	fmt.Fprintf(os.Stderr, "-bayer    4          Bayer matrix size (2, 4, 8)\n")
+}
+
+func main() {
+	var pfile, name, dither string
+	var bsize int
+	flag.StringVar(&pfile, "p", "", "palette file")
+	flag.StringVar(&name, "color", "", "palette name")
+	flag.StringVar(&dither, "dither", "none", "dithering: none, fs, atkinson, bayer")
+	flag.IntVar(&bsize, "bayer", 4, "Bayer matrix size")
+	flag.Usage = usage
+	flag.Parse()
+
+	if len(pfile) == 0 && len(name) == 0 {
+		usage()
+		os.Exit(1)
+	}
+	colors, err := palette(pfile, name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	in := os.Stdin
+	if flag.NArg() > 0 {
+		in, err = os.Open(flag.Arg(0))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		defer in.Close()
+	}
+	if err := process(os.Stdout, in, colors, dither, bsize); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
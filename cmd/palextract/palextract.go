@@ -0,0 +1,111 @@
+// palextract -- extract the dominant colors of an image as a palette
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ajstarks/utils/readpalette"
+)
+
+// native writes the palette with a comment line giving each color's share
+func native(w io.Writer, name string, sw []readpalette.Swatch) {
+	fmt.Fprintf(w, "# %s", name)
+	for _, s := range sw {
+		fmt.Fprintf(w, " %.1f%%", s.Share*100)
+	}
+	fmt.Fprintf(w, "\n%s", name)
+	for _, s := range sw {
+		fmt.Fprintf(w, " %s", readpalette.HexColor(s.Color))
+	}
+	fmt.Fprintln(w)
+}
+
+// deck writes a swatch slide: one bar per color, its width proportional to its share
+func deck(w io.Writer, name string, sw []readpalette.Swatch) {
+	fmt.Fprintln(w, "<deck>")
+	fmt.Fprintln(w, "<slide bg=\"white\" fg=\"black\">")
+	fmt.Fprintf(w, "<text xp=\"5\" yp=\"90\" sp=\"3\">%s</text>\n", readpalette.Escape(name))
+	x := 5.0
+	for _, s := range sw {
+		width := s.Share * 90
+		c := readpalette.HexColor(s.Color)
+		fmt.Fprintf(w, "<rect xp=\"%.2f\" yp=\"50\" wp=\"%.2f\" hp=\"40\" color=%q/>\n", x+width/2, width, c)
+		if width > 4 {
+			fmt.Fprintf(w, "<text xp=\"%.2f\" yp=\"25\" sp=\"1\" align=\"c\" font=\"mono\">%s</text>\n", x+width/2, c)
+			fmt.Fprintf(w, "<text xp=\"%.2f\" yp=\"21\" sp=\"1\" align=\"c\">%.1f%%</text>\n", x+width/2, s.Share*100)
+		}
+		x += width
+	}
+	fmt.Fprintln(w, "</slide>")
+	fmt.Fprintln(w, "</deck>")
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: palextract [options] image")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintf(os.Stderr, "Option    Default      Description\n")
+	fmt.Fprintf(os.Stderr, ".....................................................\n")
+	fmt.Fprintf(os.Stderr, "-n        5            number of colors\n")
+	fmt.Fprintf(os.Stderr, "-method   kmeans       clustering method (mediancut, kmeans)\n")
+	fmt.Fprintf(os.Stderr, "-name     image name   palette name\n")
+	fmt.Fprintf(os.Stderr, "-deck                  also write a deck swatch slide to the named file\n")
+}
+
+func main() {
+	var n int
+	var method, name, deckfile string
+	flag.IntVar(&n, "n", 5, "number of colors")
+	flag.StringVar(&method, "method", "kmeans", "mediancut or kmeans")
+	flag.StringVar(&name, "name", "", "palette name")
+	flag.StringVar(&deckfile, "deck", "", "deck swatch slide file")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		usage()
+		os.Exit(1)
+	}
+	filename := flag.Arg(0)
+	f, err := os.Open(filename)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	img, _, err := image.Decode(f)
+	f.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", filename, err)
+		os.Exit(1)
+	}
+	sw, err := readpalette.Extract(img, n, method)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", filename, err)
+		os.Exit(1)
+	}
+	if len(name) == 0 {
+		base := filepath.Base(filename)
+		name = strings.Join(strings.Fields(strings.TrimSuffix(base, filepath.Ext(base))), "-")
+	}
+	native(os.Stdout, name, sw)
+	if len(deckfile) > 0 {
+		df, err := os.Create(deckfile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		deck(df, name, sw)
+		if err := df.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+	}
+}
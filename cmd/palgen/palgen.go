@@ -0,0 +1,102 @@
+// palgen -- generate palettes from color harmonies and interpolated ramps
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image/color"
+	"os"
+	"strings"
+
+	"github.com/ajstarks/utils/readpalette"
+)
+
+// parsecolors parses a list of space separated colors,
+// which may have spaces within parentheses: "rgb(10, 20, 30) #fff"
+func parsecolors(s string) ([]color.NRGBA, error) {
+	f := readpalette.SplitColors(s)
+	colors := make([]color.NRGBA, len(f))
+	for i, c := range f {
+		v, err := readpalette.ParseColor(c)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %v", c, err)
+		}
+		colors[i] = v
+	}
+	return colors, nil
+}
+
+// generate makes a palette from a harmony rule applied to the seed color,
+// or a ramp through the stops; if steps is set, a harmony is expanded
+// to a ramp through its colors.
+func generate(seed, harmony, stops string, steps int, sp readpalette.Space) ([]color.NRGBA, error) {
+	var colors []color.NRGBA
+	var err error
+	if len(stops) > 0 {
+		colors, err = parsecolors(stops)
+		if err != nil {
+			return nil, err
+		}
+		if steps < 2 {
+			steps = len(colors)
+		}
+		return readpalette.Ramp(colors, steps, sp)
+	}
+	c, err := readpalette.ParseColor(seed)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %v", seed, err)
+	}
+	colors, err = readpalette.Harmony(c, harmony)
+	if err != nil || steps < 2 {
+		return colors, err
+	}
+	return readpalette.Ramp(colors, steps, sp)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintf(os.Stderr, "Option     Default         Description\n")
+	fmt.Fprintf(os.Stderr, ".....................................................\n")
+	fmt.Fprintf(os.Stderr, "-name      generated       palette name\n")
+	fmt.Fprintf(os.Stderr, "-color     steelblue       seed color for harmonies\n")
+	fmt.Fprintf(os.Stderr, "-harmony   complementary   harmony rule (%s)\n", strings.Join(readpalette.Harmonies(), ", "))
+	fmt.Fprintf(os.Stderr, "-ramp      \"\"              ramp through these colors (\"c1 c2 ...\") instead of a harmony\n")
+	fmt.Fprintf(os.Stderr, "-n         0               number of ramp steps (0: one per color)\n")
+	fmt.Fprintf(os.Stderr, "-space     oklab           interpolation space (oklab, lab, srgb)\n")
+	fmt.Fprintf(os.Stderr, "-to        native          output format\n")
+}
+
+func main() {
+	var name, seed, harmony, stops, space, to string
+	var steps int
+	flag.StringVar(&name, "name", "generated", "palette name")
+	flag.StringVar(&seed, "color", "steelblue", "seed color")
+	flag.StringVar(&harmony, "harmony", "complementary", "harmony rule")
+	flag.StringVar(&stops, "ramp", "", "ramp colors")
+	flag.IntVar(&steps, "n", 0, "ramp steps")
+	flag.StringVar(&space, "space", "oklab", "interpolation space")
+	flag.StringVar(&to, "to", "native", "output format")
+	flag.Usage = usage
+	flag.Parse()
+
+	sp, err := readpalette.ParseSpace(space)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	f, err := readpalette.ParseFormat(to)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	colors, err := generate(seed, harmony, stops, steps, sp)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	p := map[string][]string{name: readpalette.HexColors(colors)}
+	if err := readpalette.Write(os.Stdout, p, f); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
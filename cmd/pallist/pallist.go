@@ -0,0 +1,52 @@
+// pallist -- list and search the palette registry
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ajstarks/utils/readpalette"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: pallist [options] [search text]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintf(os.Stderr, "Option    Default    Description\n")
+	fmt.Fprintf(os.Stderr, ".....................................................\n")
+	fmt.Fprintf(os.Stderr, "-tag      \"\"         only palettes with this tag (gameboy, categorical, sequential, diverging, user)\n")
+	fmt.Fprintf(os.Stderr, "-min      0          minimum number of colors\n")
+	fmt.Fprintf(os.Stderr, "-max      0          maximum number of colors\n")
+	fmt.Fprintf(os.Stderr, "-p        \"\"         palette file to merge\n")
+	fmt.Fprintf(os.Stderr, "-tags     false      show tags\n")
+}
+
+func main() {
+	var q readpalette.Query
+	var pfile string
+	var showtags bool
+	flag.StringVar(&q.Tag, "tag", "", "tag")
+	flag.IntVar(&q.Min, "min", 0, "minimum number of colors")
+	flag.IntVar(&q.Max, "max", 0, "maximum number of colors")
+	flag.StringVar(&pfile, "p", "", "palette file")
+	flag.BoolVar(&showtags, "tags", false, "show tags")
+	flag.Usage = usage
+	flag.Parse()
+	q.Text = strings.Join(flag.Args(), " ")
+
+	registry := readpalette.Default()
+	if len(pfile) > 0 {
+		if err := registry.LoadFile(pfile); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+	}
+	for _, name := range registry.Search(q) {
+		colors, _ := registry.Lookup(name)
+		if showtags {
+			fmt.Printf("# %s\n", strings.Join(registry.Tags(name), " "))
+		}
+		fmt.Printf("%-24s %s\n", name, strings.Join(colors, " "))
+	}
+}
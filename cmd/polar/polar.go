@@ -1,19 +1,38 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"math"
+	"os"
 )
 
+// point is a polar coordinate and its Cartesian equivalent, as written with -json
+type point struct {
+	X     float64 `json:"x"`
+	Y     float64 `json:"y"`
+	R     float64 `json:"r"`
+	Theta float64 `json:"theta"`
+	PX    float64 `json:"px"`
+	PY    float64 `json:"py"`
+}
+
 func main() {
 	var x, y, r, theta float64
+	var jsonout bool
 	flag.Float64Var(&x, "x", 50, "x coordinate")
 	flag.Float64Var(&y, "y", 50, "y coordinate")
 	flag.Float64Var(&r, "r", 10, "radius")
 	flag.Float64Var(&theta, "t", 90, "angle (degrees)")
+	flag.BoolVar(&jsonout, "json", false, "write JSON")
 	flag.Parse()
 
 	rad := theta * (math.Pi / 180)
-	fmt.Printf("x=%g y=%g r=%g t=%g -> %g %g\n", x, y, r, theta, x+(r*math.Cos(rad)), y+(r*math.Sin(rad)))
+	px, py := x+(r*math.Cos(rad)), y+(r*math.Sin(rad))
+	if jsonout {
+		json.NewEncoder(os.Stdout).Encode(point{X: x, Y: y, R: r, Theta: theta, PX: px, PY: py})
+		return
+	}
+	fmt.Printf("x=%g y=%g r=%g t=%g -> %g %g\n", x, y, r, theta, px, py)
 }
@@ -0,0 +1,9 @@
+module github.com/ajstarks/utils/cmd/popio
+
+go 1.21.6
+
+require github.com/ajstarks/utils/imagescan v0.0.0
+
+require golang.org/x/image v0.18.0 // indirect
+
+replace github.com/ajstarks/utils/imagescan => ../../imagescan
@@ -7,15 +7,16 @@ import (
 	"fmt"
 	"image"
 	"image/color"
-	_ "image/jpeg"
 	"image/png"
 	"io"
 	"os"
+
+	"github.com/ajstarks/utils/imagescan"
 )
 
 // popin: image to raw
 func popin(w io.Writer, r io.Reader) (int, error) {
-	img, _, err := image.Decode(r)
+	img, _, err := imagescan.Decode(r)
 	if err != nil {
 		return 0, err
 	}
@@ -1,3 +1,12 @@
 module github.com/ajstarks/utils/cmd/randgen
 
 go 1.22.2
+
+require (
+	github.com/ajstarks/utils/scale v0.0.0
+	github.com/ajstarks/utils/seeds v0.0.0
+)
+
+replace github.com/ajstarks/utils/seeds => ../../seeds
+
+replace github.com/ajstarks/utils/scale => ../../scale
@@ -5,11 +5,10 @@ import (
 	"flag"
 	"fmt"
 	"math/rand/v2"
-)
 
-func vmap(v, l1, h1, l2, h2 float64) float64 {
-	return l2 + (h2-l2)*(v-l1)/(h1-l1)
-}
+	"github.com/ajstarks/utils/scale"
+	"github.com/ajstarks/utils/seeds"
+)
 
 func main() {
 	nrand := flag.Int("n", 100, "number of items")
@@ -17,15 +16,25 @@ func main() {
 	max := flag.Float64("max", 1e6, "minimum value")
 	ndec := flag.Int("dec", 3, "number of decimals")
 	xint := flag.Float64("xint", 0, "x value interval")
+	seed := flag.Uint64("seed", 0, "random seed (0 for a new seed each run)")
+	variations := flag.Int("variations", 1, "number of data sets, separated by blank lines")
 	flag.Parse()
-	xval := 0.0
 	f := fmt.Sprintf("%%.%df", *ndec)
-	for i := 0; i < *nrand; i++ {
-		if *xint > 0 {
-			fmt.Printf(f+"\t", xval)
-			xval += *xint
+	for v, s := range seeds.Int64(int64(*seed), *variations) {
+		if v > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("# randgen -seed %d\n", uint64(s))
+		rng := rand.New(rand.NewPCG(uint64(s), 0))
+		values := scale.NewLinear(0, 1, *min, *max)
+		xval := 0.0
+		for i := 0; i < *nrand; i++ {
+			if *xint > 0 {
+				fmt.Printf(f+"\t", xval)
+				xval += *xint
+			}
+			fmt.Printf(f+"\n", values.Map(rng.Float64()))
 		}
-		fmt.Printf(f+"\n", vmap(rand.Float64(), 0, 1, *min, *max))
 	}
 
 }
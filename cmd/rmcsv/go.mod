@@ -1,3 +1,7 @@
 module github.com/ajstarks/utils/cmd/rmcsv
 
-go 1.21.0
+go 1.21.6
+
+require github.com/ajstarks/utils/roadmapcsv v0.0.0
+
+replace github.com/ajstarks/utils/roadmapcsv => ../../roadmapcsv
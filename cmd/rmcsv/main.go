@@ -2,12 +2,14 @@
 package main
 
 import (
-	"encoding/csv"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
+
+	"github.com/ajstarks/utils/roadmapcsv"
 )
 
 const (
@@ -18,12 +20,25 @@ const (
 	endcat       = "\t</category>\n"
 	monthcatfmt  = "\t<category color=\"#bbbbbb\" shape=\"r\" itemheight=\"30\" vspace=\"0\">\n"
 	monthitemfmt = "\t\t<item begin=\"%d/%02d\" duration=\"1\">%s</item>\n"
-	catfmt       = "\t<category name=%q itemheight=\"%d\" vspace=\"%d\">\n"
-	itemfmt1     = "\t\t<item id=%q begin=%q duration=%q><dep dest=%q/>%s</item>\n"
-	itemfmt2     = "\t\t<item id=%q begin=%q duration=%q>%s</item>\n"
-	itemfmt3     = "\t\t<item begin=%q duration=%q>%s</item>\n"
+	catfmt       = "\t<category name=\"%s\"%s itemheight=\"%d\" vspace=\"%d\">\n"
+	spacedcatfmt = "\t<category name=\"%s\"%s>\n"
+	catdescfmt   = "\t\t<catdesc>%s</catdesc>\n"
+	cditemfmt    = "<cditem>%s</cditem>"
+	itemfmt      = "\t\t<item%s>%s%s%s</item>\n"
+	depfmt       = "<dep%s>%s</dep>"
+	emptydepfmt  = "<dep%s/>"
+	descfmt      = "<desc>%s</desc>"
+	attrfmt      = " %s=\"%s\""
 )
 
+// rootattrs are the attributes of the roadmap element, and the columns
+// of the roadmap row that give them
+var rootattrs = []struct{ attr, column string }{
+	{"title", "Category/Item"}, {"fontname", "Font"}, {"shape", "Shape"},
+	{"begin", "Begin"}, {"end", "End"}, {"catpercent", "Catpercent"}, {"scale", "Scale"},
+	{"itemheight", "Itemheight"}, {"vspace", "Vspace"}, {"header", "Header"}, {"fystart", "Fystart"},
+}
+
 type rmconfig struct {
 	title, shape, font                                string
 	begin, end, catpercent, scale, vspace, itemheight int
@@ -73,58 +88,83 @@ func process(location string, config rmconfig, w io.Writer) {
 	r.Close()
 }
 
-// csvtoxml reads the roadmap CSV, converting to XML
+// csvtoxml reads the roadmap CSV, converting to XML. A roadmap row, with
+// a scale, replaces the options for the roadmap element and the date header,
+// as the file holds its own header categories.
 func csvtoxml(w io.Writer, r io.Reader, config rmconfig) {
-	// roadmap root element
-	fmt.Fprintf(w, rmfmt, xmlesc(config.title), xmlesc(config.font), xmlesc(config.shape),
-		config.begin, config.end, config.catpercent, config.scale, config.itemheight, config.vspace)
-
-	// read categories and items from csv, write XML
-	input := csv.NewReader(r)
-	nr := 0
-	nc := 0
-	if config.dh {
-		dateheader(w, config.begin, config.end)
-	}
+	// read categories and items from csv
+	input := roadmapcsv.NewReader(r)
+	var rows []roadmapcsv.Row
+	var root roadmapcsv.Row
 	for {
-		fields, csverr := input.Read()
+		row, csverr := input.Read()
 		if csverr == io.EOF {
 			break
 		}
 		if csverr != nil {
-			fmt.Fprintf(os.Stderr, "%v %v\n", csverr, fields)
+			fmt.Fprintf(os.Stderr, "%v\n", csverr)
 			continue
 		}
-		nr++
-		// skip header
-		if nr == 1 {
+		if row.Kind() == roadmapcsv.Roadmap {
+			root = row
 			continue
 		}
-		// skip invalid fields
-		if len(fields) < 5 {
-			continue
+		rows = append(rows, row)
+	}
+	// files without spacing columns use the options for each category
+	spaced := input.Spaced()
+
+	// roadmap root element
+	if root == nil {
+		fmt.Fprintf(w, rmfmt, xmlesc(config.title), xmlesc(config.font), xmlesc(config.shape),
+			config.begin, config.end, config.catpercent, config.scale, config.itemheight, config.vspace)
+		if config.dh {
+			dateheader(w, config.begin, config.end)
 		}
-		if len(fields[0]) == 0 {
-			continue
+	} else {
+		fmt.Fprint(w, "<roadmap")
+		for _, a := range rootattrs {
+			if v := strings.TrimSpace(root[a.column]); len(v) > 0 {
+				fmt.Fprintf(w, attrfmt, a.attr, xmlesc(v))
+			}
 		}
+		fmt.Fprint(w, ">\n")
+	}
+
+	// write XML
+	nc := 0
+	for _, row := range rows {
 		// process categories
-		if len(fields[0]) > 0 && len(fields[1]) == 0 && len(fields[2]) == 0 {
+		if row.Kind() == roadmapcsv.Category {
 			nc++
 			if nc > 1 {
 				fmt.Fprint(w, endcat)
 			}
-			fmt.Fprintf(w, catfmt, xmlesc(fields[0]), config.itemheight, config.vspace)
+			if spaced {
+				fmt.Fprintf(w, spacedcatfmt, xmlesc(row["Category/Item"]), attrs(row, "Color", "Shape", "Itemheight", "Vspace", "Bline"))
+			} else {
+				fmt.Fprintf(w, catfmt, xmlesc(row["Category/Item"]), attrs(row, "Color", "Shape", "Bline"), config.itemheight, config.vspace)
+			}
+			if len(row["Description"]) > 0 {
+				var cd string
+				for _, t := range strings.Split(row["Description"], "\n") {
+					cd += fmt.Sprintf(cditemfmt, xmlesc(t))
+				}
+				fmt.Fprintf(w, catdescfmt, cd)
+			}
 			continue
 		}
 		// process items
-		switch {
-		case len(fields[3]) > 0 && len(fields[4]) > 0:
-			fmt.Fprintf(w, itemfmt1, xmlesc(fields[3]), xmlesc(fields[1]), xmlesc(fields[2]), xmlesc(fields[4]), xmlesc(fields[0]))
-		case len(fields[3]) > 0 && len(fields[4]) == 0:
-			fmt.Fprintf(w, itemfmt2, xmlesc(fields[3]), xmlesc(fields[1]), xmlesc(fields[2]), xmlesc(fields[0]))
-		default:
-			fmt.Fprintf(w, itemfmt3, xmlesc(fields[1]), xmlesc(fields[2]), xmlesc(fields[0]))
+		if nc == 0 {
+			nc++
+			fmt.Fprintf(w, catfmt, "", "", config.itemheight, config.vspace)
 		}
+		var desc string
+		if len(row["Description"]) > 0 {
+			desc = fmt.Sprintf(descfmt, xmlesc(row["Description"]))
+		}
+		fmt.Fprintf(w, itemfmt, attrs(row, "Id", "Begin", "End", "Duration", "Color", "Shape", "Milestone", "Align", "Vspace", "Bline"),
+			deps(row["Connection"]), xmlesc(row["Category/Item"]), desc)
 	}
 	// end the XML
 	fmt.Fprint(w, endrmfmt)
@@ -134,13 +174,43 @@ func csvtoxml(w io.Writer, r io.Reader, config rmconfig) {
 var xmlmap = strings.NewReplacer(
 	"&", "&amp;",
 	"<", "&lt;",
-	">", "&gt;")
+	">", "&gt;",
+	"\"", "&quot;")
 
 // xmlesc escapes XML
 func xmlesc(s string) string {
 	return xmlmap.Replace(s)
 }
 
+// attrs makes XML attributes from the non-empty fields named
+func attrs(row roadmapcsv.Row, names ...string) string {
+	var s string
+	for _, name := range names {
+		if v := row[name]; len(v) > 0 {
+			s += fmt.Sprintf(attrfmt, strings.ToLower(name), xmlesc(v))
+		}
+	}
+	return s
+}
+
+// deps makes dep elements from a connection field, as read by roadmapcsv.ParseDeps
+func deps(s string) string {
+	var x string
+	for _, d := range roadmapcsv.ParseDeps(s) {
+		a := fmt.Sprintf(attrfmt, "dest", xmlesc(d.Dest))
+		if d.BPct != 0 || d.EPct != 0 {
+			a += fmt.Sprintf(attrfmt, "bpct", strconv.FormatFloat(d.BPct, 'f', -1, 64)) +
+				fmt.Sprintf(attrfmt, "epct", strconv.FormatFloat(d.EPct, 'f', -1, 64))
+		}
+		if len(d.Desc) == 0 {
+			x += fmt.Sprintf(emptydepfmt, a)
+		} else {
+			x += fmt.Sprintf(depfmt, a, xmlesc(d.Desc))
+		}
+	}
+	return x
+}
+
 // dateheader makes a date header
 func dateheader(w io.Writer, begin, end int) {
 	// years
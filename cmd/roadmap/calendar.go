@@ -0,0 +1,254 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ajstarks/utils/locale"
+)
+
+// isodate is the layout of calendar dates
+const isodate = "2006-01-02"
+
+// Date is a point in time in years, written as a year, 2026 or 2026.5,
+// or as an ISO date, 2026-03-15
+type Date struct {
+	Year float64
+	ISO  bool // written as an ISO date
+}
+
+// UnmarshalXMLAttr reads a year or an ISO date
+func (d *Date) UnmarshalXMLAttr(attr xml.Attr) error {
+	var err error
+	*d, err = readdate(attr.Value)
+	return err
+}
+
+// String writes a date as it was read: as an ISO date, or as a year
+func (d Date) String() string {
+	if d.ISO {
+		return isotime(d.Year)
+	}
+	return strconv.FormatFloat(d.Year, 'f', -1, 64)
+}
+
+// readdate reads a year or an ISO date, remembering which
+func readdate(s string) (Date, error) {
+	v, err := parsedate(s)
+	return Date{Year: v, ISO: isISO(strings.TrimSpace(s))}, err
+}
+
+// isotime writes a time in years as an ISO date, allowing for rounding
+func isotime(v float64) string {
+	return yeartime(v).Add(time.Minute).Truncate(24 * time.Hour).Format(isodate)
+}
+
+// isISO reports whether a string is written as an ISO date
+func isISO(s string) bool {
+	return strings.Count(s, "-") == 2
+}
+
+// parsedate reads a year or an ISO date; empty is zero
+func parsedate(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if len(s) == 0 {
+		return 0, nil
+	}
+	if isISO(s) {
+		t, err := time.Parse(isodate, s)
+		if err != nil {
+			return 0, fmt.Errorf("bad date %q (use YYYY-MM-DD)", s)
+		}
+		return years(t), nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("bad year %q", s)
+	}
+	return v, nil
+}
+
+// years converts a time to years
+func years(t time.Time) float64 {
+	y := t.Year()
+	start := time.Date(y, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(y+1, 1, 1, 0, 0, 0, 0, time.UTC)
+	return float64(y) + float64(t.Sub(start))/float64(end.Sub(start))
+}
+
+// yeartime converts years to a time
+func yeartime(v float64) time.Time {
+	y := math.Floor(v)
+	start := time.Date(int(y), 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(int(y)+1, 1, 1, 0, 0, 0, 0, time.UTC)
+	return start.Add(time.Duration((v - y) * float64(end.Sub(start))))
+}
+
+// itemspan returns when an item begins and ends, in years.
+// Items begin at a part of a year, "2026/3", where the scale is the
+// number of parts in a year, and last for a duration in parts; or they
+// begin on a date, "2026-03-15", and last for a duration in days.
+// An end, the last part or the last day, may replace the duration.
+func itemspan(item Item, scale float64) (begin, end float64, err error) {
+	if scale <= 0 {
+		scale = 1.0
+	}
+	if isISO(item.Begin) {
+		b, err := time.Parse(isodate, item.Begin)
+		if err != nil {
+			return 0, 0, fmt.Errorf("bad begin %q (use YYYY-MM-DD or year/part)", item.Begin)
+		}
+		begin = years(b)
+		if len(item.End) > 0 {
+			e, err := time.Parse(isodate, item.End)
+			if err != nil {
+				return 0, 0, fmt.Errorf("bad end %q (use YYYY-MM-DD)", item.End)
+			}
+			return begin, years(e.AddDate(0, 0, 1)), nil
+		}
+		days, err := duration(item.Duration)
+		if err != nil {
+			return 0, 0, err
+		}
+		return begin, years(b.Add(time.Duration(days * 24 * float64(time.Hour)))), nil
+	}
+
+	begin, err = yearpart(item.Begin, scale)
+	if err != nil {
+		return 0, 0, fmt.Errorf("bad begin %q (use year/part or YYYY-MM-DD)", item.Begin)
+	}
+	if len(item.End) > 0 {
+		end, err = yearpart(item.End, scale)
+		if err != nil {
+			return 0, 0, fmt.Errorf("bad end %q (use year/part)", item.End)
+		}
+		return begin, end + 1/scale, nil
+	}
+	parts, err := duration(item.Duration)
+	if err != nil {
+		return 0, 0, err
+	}
+	return begin, begin + parts/scale, nil
+}
+
+// yearpart reads the beginning of a part of a year, "2026/3"
+func yearpart(s string, scale float64) (float64, error) {
+	dt := strings.SplitN(s, "/", 2)
+	if len(dt) != 2 {
+		return 0, fmt.Errorf("no part")
+	}
+	year, err := strconv.ParseFloat(dt[0], 64)
+	if err != nil {
+		return 0, err
+	}
+	part, err := strconv.ParseFloat(dt[1], 64)
+	if err != nil {
+		return 0, err
+	}
+	return year + (part-1)/scale, nil
+}
+
+// duration reads a duration; empty is zero
+func duration(s string) (float64, error) {
+	if len(s) == 0 {
+		return 0, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("bad duration %q", s)
+	}
+	return v, nil
+}
+
+// period is a labelled interval of a header row, in years
+type period struct {
+	begin, end float64
+	label      string
+}
+
+// periods divides the time from begin to end into weeks, months or
+// quarters; quarters are those of a fiscal year beginning in the month fystart
+func periods(kind string, begin, end float64, fystart int, loc locale.Locale) ([]period, error) {
+	if fystart < 1 || fystart > 12 {
+		fystart = 1
+	}
+	var (
+		t    time.Time
+		next func(time.Time) time.Time
+	)
+	b := yeartime(begin)
+	switch kind {
+	case "week":
+		t = time.Date(b.Year(), b.Month(), b.Day()-(int(b.Weekday())+6)%7, 0, 0, 0, 0, time.UTC) // Monday
+		next = func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }
+	case "month":
+		t = time.Date(b.Year(), b.Month(), 1, 0, 0, 0, 0, time.UTC)
+		next = func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }
+	case "quarter":
+		t = time.Date(b.Year(), b.Month()-time.Month(fiscalmonth(b, fystart)%3), 1, 0, 0, 0, 0, time.UTC)
+		next = func(t time.Time) time.Time { return t.AddDate(0, 3, 0) }
+	default:
+		return nil, fmt.Errorf("unknown header %q (use week, month, quarter)", kind)
+	}
+
+	var p []period
+	for ; years(t) < end; t = next(t) {
+		pb, pe := math.Max(years(t), begin), math.Min(years(next(t)), end)
+		if pe <= pb {
+			continue
+		}
+		var label string
+		switch kind {
+		case "week":
+			_, w := t.ISOWeek()
+			label = strconv.Itoa(w)
+		case "month":
+			label = loc.FormatTime(t, "Jan")
+		case "quarter":
+			label = quarter(t, fystart)
+		}
+		p = append(p, period{begin: pb, end: pe, label: label})
+	}
+	return p, nil
+}
+
+// fiscalmonth returns the month of the fiscal year, from 0
+func fiscalmonth(t time.Time, fystart int) int {
+	return (int(t.Month()) - fystart + 12) % 12
+}
+
+// quarter labels the quarter beginning at t: "Q1 2026" for calendar
+// years, "Q1 FY27" for fiscal years, which are named by the year they end
+func quarter(t time.Time, fystart int) string {
+	q := fiscalmonth(t, fystart)/3 + 1
+	if fystart == 1 {
+		return fmt.Sprintf("Q%d %d", q, t.Year())
+	}
+	fy := t.Year()
+	if int(t.Month()) >= fystart {
+		fy++
+	}
+	return fmt.Sprintf("Q%d FY%02d", q, fy%100)
+}
+
+// fits reports whether a header label fits a width, in item text
+func fits(label string, w float64) bool {
+	return float64(len([]rune(label)))**ifs*0.6 < w
+}
+
+// parsetoday reads the date of the today marker: "now", or an ISO date
+func parsetoday(s string) (time.Time, error) {
+	if s == "now" {
+		n := time.Now()
+		return time.Date(n.Year(), n.Month(), n.Day(), 0, 0, 0, 0, time.UTC), nil
+	}
+	t, err := time.Parse(isodate, s)
+	if err != nil {
+		return t, fmt.Errorf("bad date for -today %q (use now or YYYY-MM-DD)", s)
+	}
+	return t, nil
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ajstarks/utils/roadmapcsv"
+)
+
+// rmcsv renders the roadmap data as CSV; see the roadmapcsv package for the columns
+func rmcsv(r Roadmap, w io.Writer) {
+	out := csv.NewWriter(w)
+	out.Write(roadmapcsv.Columns)
+	empty := make([]string, len(roadmapcsv.Columns))
+	out.Write(roadmapcsv.Row{
+		"Category/Item": r.Title,
+		"Begin":         r.Begin.String(),
+		"End":           r.End.String(),
+		"Scale":         strconv.FormatFloat(r.Scale, 'f', -1, 64),
+		"Catpercent":    roadmapcsv.Num(r.Catpercent),
+		"Vspace":        roadmapcsv.Num(r.Vspace),
+		"Itemheight":    roadmapcsv.Num(r.Itemheight),
+		"Shape":         r.Shape,
+		"Header":        r.Header,
+		"Fystart":       roadmapcsv.Num(float64(r.Fystart)),
+		"Font":          r.Fontname,
+	}.Fields())
+	for _, cat := range r.Category {
+		var cd []string
+		for _, cdi := range cat.Catdesc.Cditem {
+			cd = append(cd, cdi.Cdtext)
+		}
+		out.Write(empty) // category name, after an empty row
+		out.Write(roadmapcsv.Row{
+			"Category/Item": cat.Name,
+			"Color":         cat.Color,
+			"Shape":         cat.Shape,
+			"Description":   strings.Join(cd, "\n"),
+			"Vspace":        cat.Vspace,
+			"Itemheight":    strconv.FormatFloat(cat.Itemheight, 'f', -1, 64), // never empty, so nameless categories are kept
+			"Bline":         cat.Bline,
+		}.Fields())
+		for _, item := range cat.Item {
+			out.Write(roadmapcsv.Row{
+				"Category/Item": item.Text,
+				"Begin":         item.Begin,
+				"Duration":      item.Duration,
+				"Id":            item.Id,
+				"Connection":    formatdeps(item.Dep),
+				"End":           item.End,
+				"Color":         item.Color,
+				"Shape":         item.Shape,
+				"Milestone":     item.Milestone,
+				"Align":         item.Align,
+				"Description":   item.Desc,
+				"Vspace":        roadmapcsv.Num(item.Vspace),
+				"Bline":         item.Bline,
+			}.Fields())
+		}
+	}
+	out.Flush()
+	if err := out.Error(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+	}
+}
+
+// number reads a number from a field, reporting a bad one
+func number(row roadmapcsv.Row, column string) float64 {
+	v, err := row.Number(column)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+	}
+	return v
+}
+
+// readcsv reads a CSV version of a roadmap. The roadmap row gives the
+// roadmap's attributes as written; files without one have the defaults of
+// earlier versions, with the title, begin and end given by -csvparam,
+// which overrides the row. Categories in files without vspace and
+// itemheight columns are spaced as in earlier versions.
+func readCSV(r io.Reader) Roadmap {
+	rm := Roadmap{
+		Scale:      12,
+		Catpercent: 15,
+		Vspace:     45,
+		Itemheight: 40,
+		Shape:      "r",
+		Fontname:   "Calibri,sans-serif",
+	}
+	input := roadmapcsv.NewReader(r)
+	for {
+		row, csverr := input.Read()
+		if csverr == io.EOF {
+			break
+		}
+		if csverr != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", csverr)
+			continue
+		}
+		catvspace, catheight := "", 0.0
+		if !input.Spaced() {
+			catvspace, catheight = "45", 40
+		}
+		switch row.Kind() {
+		case roadmapcsv.Roadmap:
+			var err error
+			if rm.Begin, err = readdate(row["Begin"]); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+			}
+			if rm.End, err = readdate(row["End"]); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+			}
+			rm.Title = row["Category/Item"]
+			rm.Scale = number(row, "Scale")
+			rm.Catpercent = number(row, "Catpercent")
+			rm.Vspace = number(row, "Vspace")
+			rm.Itemheight = number(row, "Itemheight")
+			rm.Fystart = int(number(row, "Fystart"))
+			rm.Shape = row["Shape"]
+			rm.Fontname = row["Font"]
+			rm.Header = row["Header"]
+		case roadmapcsv.Category:
+			c := Category{Name: row["Category/Item"], Color: row["Color"], Shape: row["Shape"],
+				Vspace: catvspace, Itemheight: catheight, Bline: row["Bline"]}
+			if input.Spaced() {
+				c.Vspace = strings.TrimSpace(row["Vspace"])
+				c.Itemheight = number(row, "Itemheight")
+			}
+			if len(row["Description"]) > 0 {
+				for _, t := range strings.Split(row["Description"], "\n") {
+					c.Catdesc.Cditem = append(c.Catdesc.Cditem, cditem{Cdtext: t})
+				}
+			}
+			rm.Category = append(rm.Category, c)
+		default:
+			if len(rm.Category) == 0 { // items before the first category
+				rm.Category = append(rm.Category, Category{Vspace: "45", Itemheight: 40})
+			}
+			cat := &rm.Category[len(rm.Category)-1]
+			cat.Item = append(cat.Item, Item{
+				Text:      row["Category/Item"],
+				Begin:     row["Begin"],
+				Duration:  row["Duration"],
+				Id:        row["Id"],
+				Dep:       parsedeps(row["Connection"]),
+				End:       row["End"],
+				Color:     row["Color"],
+				Shape:     row["Shape"],
+				Milestone: row["Milestone"],
+				Align:     row["Align"],
+				Desc:      row["Description"],
+				Vspace:    number(row, "Vspace"),
+				Bline:     row["Bline"],
+			})
+		}
+	}
+	rp := strings.Split(*csvparam, ",")
+	if len(rp) == 3 {
+		rm.Title = rp[0]
+		rm.Begin, _ = readdate(rp[1])
+		rm.End, _ = readdate(rp[2])
+	}
+	dumprm(rm, os.Stderr)
+	return rm
+}
+
+// formatdeps writes dependencies for the Connection column
+func formatdeps(deps []Dep) string {
+	d := make([]roadmapcsv.Dep, len(deps))
+	for i, dep := range deps {
+		d[i] = roadmapcsv.Dep{Dest: dep.Dest, BPct: dep.BPct, EPct: dep.EPct, Desc: dep.Desc}
+	}
+	return roadmapcsv.FormatDeps(d)
+}
+
+// parsedeps reads dependencies from the Connection column
+func parsedeps(s string) []Dep {
+	var deps []Dep
+	for _, d := range roadmapcsv.ParseDeps(s) {
+		deps = append(deps, Dep{Dest: d.Dest, BPct: d.BPct, EPct: d.EPct, Desc: d.Desc})
+	}
+	return deps
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/hex"
+	"math"
+
+	"github.com/ajstarks/utils/markup"
+)
+
+// deckfont is the font used in deck markup, which names fonts by role
+const deckfont = "sans"
+
+// deckrenderer draws roadmaps as deck slides, one per roadmap, converting
+// pixels to percentages of the canvas, with y increasing up the slide
+type deckrenderer struct {
+	deck markup.Drawer
+}
+
+// x converts a horizontal position
+func (d *deckrenderer) x(v float64) float64 { return v / *width * 100 }
+
+// y converts a vertical position
+func (d *deckrenderer) y(v float64) float64 { return 100 - v / *height * 100 }
+
+// w converts a width or a size; deck sizes are relative to the width
+func (d *deckrenderer) w(v float64) float64 { return v / *width * 100 }
+
+// h converts a height
+func (d *deckrenderer) h(v float64) float64 { return v / *height * 100 }
+
+// text draws text aligned at start, middle or end
+func (d *deckrenderer) text(x, y float64, s, align string, size float64, color string) {
+	switch align {
+	case "start":
+		d.deck.Text(d.x(x), d.y(y), s, deckfont, d.w(size), color)
+	case "end":
+		d.deck.TextEnd(d.x(x), d.y(y), s, deckfont, d.w(size), color)
+	default:
+		d.deck.TextMid(d.x(x), d.y(y), s, deckfont, d.w(size), color)
+	}
+}
+
+// start begins the slide, and draws the title
+func (d *deckrenderer) start(title string, x, y float64, font string) {
+	d.deck.StartSlide(*bgcolor, "black")
+	d.text(x, y, title, "start", *tfs, "black")
+}
+
+// header draws a cell of a header row, labelled if the label fits
+func (d *deckrenderer) header(x, y, w, h float64, label string) {
+	d.border(x, y, x, y+h)
+	d.border(x, y+h, x+w, y+h)
+	if fits(label, w) {
+		d.text(x+w/2, y+h/2+*ifs/3, label, "middle", *ifs, "#666666")
+	}
+}
+
+// shade shades past time
+func (d *deckrenderer) shade(x, y, w, h float64) {
+	d.deck.Rect(d.x(x+w/2), d.y(y+h/2), d.w(w), d.h(h), "#888888", 15)
+}
+
+// marker draws the today line, labelled above
+func (d *deckrenderer) marker(x, y1, y2 float64, label string) {
+	d.deck.Line(d.x(x), d.y(y1), d.x(x), d.y(y2), d.w(1.5), markercolor)
+	d.text(x, y1-2, label, "middle", *ifs*0.8, markercolor)
+}
+
+// catlabel draws a category label
+func (d *deckrenderer) catlabel(x, y, leading float64, label []string) {
+	for _, t := range label {
+		d.text(x, y, t, *lalign, *cfs, "black")
+		y += leading
+	}
+}
+
+// catdesc draws a category description
+func (d *deckrenderer) catdesc(x, y, leading float64, lines []string) {
+	for _, t := range lines {
+		d.text(x, y, t, "start", *ifs, "red")
+		y += leading
+	}
+}
+
+// item draws a roadmap item
+func (d *deckrenderer) item(t string, x, y, w, h float64, shape, color, align string, milestone bool) {
+	fill, op := deckcolor(color)
+	cx, cy := d.x(x+w/2), d.y(y+h/2)
+	if len(t) == 0 {
+		shape = "r"
+	}
+	switch shape {
+	case "e":
+		d.deck.Ellipse(cx, cy, d.w(w), d.h(h), fill, op)
+	case "c":
+		d.deck.Circle(cx, cy, d.w(h), fill, op)
+	case "a":
+		end := x + w
+		ap := end - h/2
+		xp := []float64{d.x(x), d.x(ap), d.x(end), d.x(ap), d.x(x)}
+		yp := []float64{d.y(y), d.y(y), d.y(y + h/2), d.y(y + h), d.y(y + h)}
+		d.deck.Polygon(xp, yp, fill, op)
+	case "l":
+		yl := d.y((y + (h / 2)) + ((*ifs / 4) - (*ifs / 3)))
+		d.deck.Line(d.x(x), yl, d.x(x+w), yl, d.w(*ifs), fill, op)
+	default: // rectangles; deck has no rounded ones
+		d.deck.Rect(cx, cy, d.w(w), d.h(h), fill, op)
+	}
+	if milestone {
+		t += " \u2605"
+	}
+	tx := x
+	switch align {
+	case "start":
+		tx += 1.0
+	case "end":
+		tx += w
+	default:
+		tx += w / 2
+	}
+	d.text(tx, (y+(h/2))+(*ifs/4), t, align, *ifs, textcolor(color))
+}
+
+// itemdesc draws the wrapped lines of an item description
+func (d *deckrenderer) itemdesc(x, y, leading float64, lines []string, font, align string) {
+	for _, t := range lines {
+		d.text(x, y, t, align, *ifs, *descolor)
+		y += leading
+	}
+}
+
+// startlinks begins the connections
+func (d *deckrenderer) startlinks() {}
+
+// highlight outlines an item on the critical path
+func (d *deckrenderer) highlight(x, y, w, h float64) {
+	xp := []float64{d.x(x), d.x(x + w), d.x(x + w), d.x(x), d.x(x)}
+	yp := []float64{d.y(y), d.y(y), d.y(y + h), d.y(y + h), d.y(y)}
+	d.deck.Polyline(xp, yp, d.w(3), *critcolor)
+}
+
+// link connects items with a curve, ending in a dot; links on the
+// critical path are solid
+func (d *deckrenderer) link(bx, by, cx, cy, ex, ey float64, desc string, descy float64, critical bool) {
+	if critical {
+		d.deck.Curve(d.x(bx), d.y(by), d.x(cx), d.y(cy), d.x(ex), d.y(ey), d.w(3), *critcolor)
+	} else {
+		d.deck.Curve(d.x(bx), d.y(by), d.x(cx), d.y(cy), d.x(ex), d.y(ey), d.w(2), *concolor, 60)
+	}
+	d.deck.Circle(d.x(ex), d.y(ey), d.w(8), *concolor, 30)
+	if len(desc) > 0 {
+		d.text(ex, descy, desc, "middle", *ifs*0.6, *descolor)
+	}
+}
+
+// endlinks ends the connections
+func (d *deckrenderer) endlinks() {}
+
+// border draws a border line
+func (d *deckrenderer) border(x1, y1, x2, y2 float64) {
+	d.deck.Line(d.x(x1), d.y(y1), d.x(x2), d.y(y2), d.w(0.75), "#BBBBBB")
+}
+
+// end ends the slide
+func (d *deckrenderer) end() {
+	d.deck.EndSlide()
+}
+
+// deckcolor splits a color with alpha, #RRGGBBAA, into
+// a color and an opacity percentage, to two decimals
+func deckcolor(s string) (string, float64) {
+	if len(s) == 9 && s[0] == '#' {
+		o, err := hex.DecodeString(s[7:9])
+		if err == nil {
+			return s[0:7], math.Round(float64(o[0])/255*100*100) / 100
+		}
+	}
+	return s, 100
+}
@@ -1,5 +1,28 @@
 module github.com/ajstarks/utils/cmd/roadmap
 
-go 1.21.0
+go 1.21.6
 
-require github.com/ajstarks/gensvg v0.0.0-20210923152200-4042c242e95e
+require (
+	github.com/ajstarks/gensvg v0.0.0-20210923152200-4042c242e95e
+	github.com/ajstarks/utils/markup v0.0.0
+	github.com/ajstarks/utils/settings v0.0.0
+	github.com/ajstarks/utils/source v0.0.0
+)
+
+require (
+	github.com/ajstarks/utils/locale v0.0.0
+	github.com/ajstarks/utils/readpalette v0.0.0 // indirect
+	github.com/ajstarks/utils/roadmapcsv v0.0.0
+)
+
+replace github.com/ajstarks/utils/settings => ../../settings
+
+replace github.com/ajstarks/utils/source => ../../source
+
+replace github.com/ajstarks/utils/markup => ../../markup
+
+replace github.com/ajstarks/utils/readpalette => ../../readpalette
+
+replace github.com/ajstarks/utils/locale => ../../locale
+
+replace github.com/ajstarks/utils/roadmapcsv => ../../roadmapcsv
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// epsilon absorbs rounding when items meet end to begin
+const epsilon = 1e-9
+
+// problem is something wrong with a roadmap
+type problem struct {
+	cat, item string
+	msg       string
+}
+
+// lintitem is an item with where it is, and when
+type lintitem struct {
+	Item
+	cat        string
+	ci, row    int // category index, and row within it
+	begin, end float64
+	ok         bool // the span is valid
+}
+
+// lint checks a roadmap, writing its problems, and returns how many there are
+func lint(r Roadmap, name string, w io.Writer) int {
+	var problems []problem
+	report := func(it *lintitem, format string, args ...interface{}) {
+		p := problem{msg: fmt.Sprintf(format, args...)}
+		if it != nil {
+			p.cat, p.item = it.cat, itemname(it.Item)
+		}
+		problems = append(problems, p)
+	}
+
+	if r.End.Year <= r.Begin.Year {
+		report(nil, "the roadmap ends (%v) before it begins (%v)", r.End, r.Begin)
+	}
+
+	// items, their rows and spans
+	var items []*lintitem
+	ids := map[string]*lintitem{}
+	for ci, cat := range r.Category {
+		row := 0
+		for _, item := range cat.Item {
+			it := &lintitem{Item: item, cat: catname(cat, ci), ci: ci, row: row}
+			items = append(items, it)
+			if rowspace(cat, item) > 0 {
+				row++
+			}
+			if len(item.Id) > 0 {
+				if first, ok := ids[item.Id]; ok {
+					report(it, "duplicate id %q (also %s / %s)", item.Id, first.cat, itemname(first.Item))
+				} else {
+					ids[item.Id] = it
+				}
+			}
+			var err error
+			it.begin, it.end, err = itemspan(item, r.Scale)
+			if err != nil {
+				report(it, "%v", err)
+				continue
+			}
+			if it.end < it.begin {
+				report(it, "ends before it begins")
+				continue
+			}
+			it.ok = true
+			if it.begin < r.Begin.Year-epsilon {
+				report(it, "begins before the roadmap begins (%v)", r.Begin)
+			}
+			if it.end > r.End.Year+epsilon {
+				report(it, "ends after the roadmap ends (%v)", r.End)
+			}
+		}
+	}
+
+	// dependencies
+	for _, it := range items {
+		for _, d := range it.Dep {
+			if _, ok := ids[d.Dest]; !ok {
+				report(it, "unknown dependency %q", d.Dest)
+			}
+		}
+	}
+	for _, cycle := range cycles(items, ids) {
+		report(ids[cycle[0]], "dependency cycle: %s", strings.Join(cycle, " -> "))
+	}
+
+	// overlaps within a row
+	for i, a := range items {
+		for _, b := range items[i+1:] {
+			if a.ci != b.ci || a.row != b.row || !a.ok || !b.ok {
+				continue
+			}
+			if a.begin < b.end-epsilon && b.begin < a.end-epsilon {
+				report(b, "overlaps %s", itemname(a.Item))
+			}
+		}
+	}
+
+	for _, p := range problems {
+		if len(p.cat) == 0 {
+			fmt.Fprintf(w, "%s: %s\n", name, p.msg)
+			continue
+		}
+		fmt.Fprintf(w, "%s: %s / %s: %s\n", name, p.cat, p.item, p.msg)
+	}
+	return len(problems)
+}
+
+// rowspace returns the space after an item, as drawrm lays it out;
+// items with no space after them share a row with the next
+func rowspace(cat Category, item Item) float64 {
+	if item.Vspace > 0 {
+		return item.Vspace
+	}
+	if len(cat.Vspace) == 0 {
+		return 0
+	}
+	v, _ := strconv.ParseFloat(cat.Vspace, 64)
+	return v
+}
+
+// cycles finds the cycles of dependencies, each as the ids around it,
+// beginning and ending with the same id, and each reported once
+func cycles(items []*lintitem, ids map[string]*lintitem) [][]string {
+	const (
+		unseen = iota
+		active
+		done
+	)
+	var (
+		found [][]string
+		path  []string
+		state = map[string]int{}
+		visit func(id string)
+	)
+	visit = func(id string) {
+		state[id] = active
+		path = append(path, id)
+		deps := ids[id].Dep
+		for _, d := range deps {
+			if _, ok := ids[d.Dest]; !ok {
+				continue
+			}
+			switch state[d.Dest] {
+			case unseen:
+				visit(d.Dest)
+			case active:
+				for i := range path {
+					if path[i] == d.Dest {
+						c := append(append([]string{}, path[i:]...), d.Dest)
+						found = append(found, c)
+						break
+					}
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = done
+	}
+
+	var order []string
+	for _, it := range items {
+		if len(it.Id) > 0 && ids[it.Id] == it {
+			order = append(order, it.Id)
+		}
+	}
+	sort.Strings(order)
+	for _, id := range order {
+		if state[id] == unseen {
+			visit(id)
+		}
+	}
+	return found
+}
+
+// catname names a category, or numbers it if it has no name
+func catname(cat Category, i int) string {
+	if len(cat.Name) > 0 {
+		return strings.ReplaceAll(cat.Name, "\\n", " ")
+	}
+	return fmt.Sprintf("category %d", i+1)
+}
+
+// itemname names an item by its text, or its id, or when it begins
+func itemname(item Item) string {
+	if t := strings.TrimSpace(item.Text); len(t) > 0 {
+		return t
+	}
+	if len(item.Id) > 0 {
+		return "item " + item.Id
+	}
+	return "item at " + item.Begin
+}
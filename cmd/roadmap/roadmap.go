@@ -2,7 +2,6 @@
 package main
 
 import (
-	"encoding/csv"
 	"encoding/hex"
 	"encoding/xml"
 	"flag"
@@ -12,8 +11,13 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ajstarks/gensvg"
+	"github.com/ajstarks/utils/locale"
+	"github.com/ajstarks/utils/markup"
+	"github.com/ajstarks/utils/settings"
+	"github.com/ajstarks/utils/source"
 )
 
 // Geometry defines the dimensions of objects
@@ -24,9 +28,11 @@ type Geometry struct {
 // Roadmap describes the structure of the roadmap
 type Roadmap struct {
 	Title      string     `xml:"title,attr"`
-	Begin      float64    `xml:"begin,attr"`
-	End        float64    `xml:"end,attr"`
+	Begin      Date       `xml:"begin,attr"`
+	End        Date       `xml:"end,attr"`
 	Scale      float64    `xml:"scale,attr"`
+	Header     string     `xml:"header,attr"`
+	Fystart    int        `xml:"fystart,attr"`
 	Catpercent float64    `xml:"catpercent,attr"`
 	Vspace     float64    `xml:"vspace,attr"`
 	Itemheight float64    `xml:"itemheight,attr"`
@@ -68,6 +74,7 @@ type Dep struct {
 type Item struct {
 	Id        string  `xml:"id,attr"`
 	Begin     string  `xml:"begin,attr"`
+	End       string  `xml:"end,attr"`
 	Duration  string  `xml:"duration,attr"`
 	Color     string  `xml:"color,attr"`
 	Milestone string  `xml:"milestone,attr"`
@@ -84,30 +91,51 @@ type Item struct {
 	H         float64
 }
 
-// command line variables
-var (
-	width       = flag.Float64("w", 1024, "width")
-	height      = flag.Float64("h", 768, "height")
-	lmargin     = flag.Float64("margin", 10, "margin")
-	twrap       = flag.Float64("wrap", 20, "text wrap")
-	tfs         = flag.Float64("tfs", 24, "title font size (px)")
-	cfs         = flag.Float64("cfs", 14, "category font size (px)")
-	ifs         = flag.Float64("ifs", 12, "item fontsize (px)")
-	lalign      = flag.String("align", "end", "label alignment")
-	topborder   = flag.Bool("tb", false, "top border")
-	botborder   = flag.Bool("bb", false, "bottom border")
-	leftborder  = flag.Bool("lb", true, "left border")
+// command line variables, defined by flags
+var width, height, lmargin, twrap, tfs, cfs, ifs *float64
+var lalign, inputformat, bgcolor, curves, csvparam, csvout, descolor, concolor, style, header, todayflag, localename, critcolor, schedflag *string
+var topborder, botborder, leftborder, rightborder, catborder, boldcat, descend, past, lintflag *bool
+var fystart *int
+
+// flags defines the command line options
+func flags() {
+	width = flag.Float64("w", 1024, "width")
+	height = flag.Float64("h", 768, "height")
+	lmargin = flag.Float64("margin", 10, "margin")
+	twrap = flag.Float64("wrap", 20, "text wrap")
+	tfs = flag.Float64("tfs", 24, "title font size (px)")
+	cfs = flag.Float64("cfs", 14, "category font size (px)")
+	ifs = flag.Float64("ifs", 12, "item fontsize (px)")
+	lalign = flag.String("align", "end", "label alignment")
+	topborder = flag.Bool("tb", false, "top border")
+	botborder = flag.Bool("bb", false, "bottom border")
+	leftborder = flag.Bool("lb", true, "left border")
 	rightborder = flag.Bool("rb", false, "right border")
-	catborder   = flag.Bool("cb", false, "category border")
-	boldcat     = flag.Bool("b", false, "bold categories")
-	descend     = flag.Bool("de", true, "description at the end of the item")
+	catborder = flag.Bool("cb", false, "category border")
+	boldcat = flag.Bool("b", false, "bold categories")
+	descend = flag.Bool("de", true, "description at the end of the item")
 	inputformat = flag.String("format", "xml", "input format")
-	bgcolor     = flag.String("bg", "white", "background color")
-	curves      = flag.String("curves", "0,0", "curve line connections")
-	csvparam    = flag.String("csvparam", "", "parameters for CSV read (title,begin,end)")
-	csvout      = flag.String("csv", "", "write CSV to specified file")
-	descolor    = flag.String("dc", "red", "description color")
-	concolor    = flag.String("cc", "red", "connection color")
+	bgcolor = flag.String("bg", "white", "background color")
+	curves = flag.String("curves", "0,0", "curve line connections")
+	csvparam = flag.String("csvparam", "", "parameters for CSV read (title,begin,end)")
+	csvout = flag.String("csv", "", "write CSV to specified file")
+	descolor = flag.String("dc", "red", "description color")
+	concolor = flag.String("cc", "red", "connection color")
+	style = flag.String("style", "svg", "output style (svg, deck, decksh)")
+	header = flag.String("header", "", "header rows (week, month, quarter), comma separated")
+	fystart = flag.Int("fystart", 0, "month the fiscal year begins (1-12)")
+	todayflag = flag.String("today", "", "mark today (now, or YYYY-MM-DD)")
+	past = flag.Bool("past", false, "shade the time before today")
+	localename = flag.String("locale", "", "locale for month names (as en-US, de-DE or fr)")
+	lintflag = flag.Bool("lint", false, "report problems instead of drawing, exiting non-zero if there are any")
+	critcolor = flag.String("critical", "", "highlight the critical path in this color")
+	schedflag = flag.String("schedule", "", "report the schedule instead of drawing (text, json)")
+}
+
+var (
+	loc      locale.Locale // for month names
+	today    time.Time     // zero unless marked or shaded
+	problems int           // problems found by -lint, -schedule or -critical, and files that could not be read
 )
 
 const (
@@ -125,57 +153,123 @@ const (
 	strokefmt    = "stroke:%s;%s"
 	categoryfmt  = "text-anchor:start;font-size:%.2fpx"
 	catgstylefmt = "text-anchor:middle;font-family:"
+	headerfmt    = "fill:none;stroke:#BBBBBB;stroke-width:0.75px"
+	headtextfmt  = "text-anchor:middle;fill:#666666;font-size:%.2fpx"
+	shadefmt     = "fill:#888888;fill-opacity:0.15"
+	markerfmt    = "stroke:%s;stroke-width:1.5px"
+	markertxtfmt = "text-anchor:middle;fill:%s;font-size:%.2fpx"
+	markercolor  = "#3366CC"
+	critfmt      = "fill:none;stroke:%s;stroke-width:3px"
+	critlinkfmt  = "stroke:%s;stroke-width:3;stroke-opacity:1;stroke-dasharray:none"
 )
 
 // main: process roadmap files on the command line,
 // use stdin if no files specified.
 func main() {
-	flag.Parse()
+	flags()
+	source.Flags()
+	settings.Parse()
 	files := flag.Args()
 	nf := len(files)
-	canvas := gensvg.New(os.Stdout)
+
+	var err error
+	loc, err = locale.Lookup(*localename)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	if len(*todayflag) > 0 {
+		today, err = parsetoday(*todayflag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+	} else if *past {
+		today, _ = parsetoday("now")
+	}
+
+	// reports, and the critical path, exit non-zero on problems, after the output ends
+	if *lintflag || len(*schedflag) > 0 || len(*critcolor) > 0 {
+		defer func() {
+			if problems > 0 {
+				os.Exit(1)
+			}
+		}()
+	}
+
+	// SVG is one document per roadmap, deck markup one slide per roadmap
+	var rd renderer
+	switch {
+	case *schedflag != "" && *schedflag != "text" && *schedflag != "json":
+		fmt.Fprintf(os.Stderr, "unknown schedule format %q (use text, json)\n", *schedflag)
+		os.Exit(1)
+	case *lintflag || len(*schedflag) > 0:
+		// reporting, not drawing
+	case *style == "svg":
+		rd = &svgrenderer{canvas: gensvg.New(os.Stdout)}
+	default:
+		deck, err := markup.New(os.Stdout, *style, int(*width), int(*height))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		deck.StartDeck()
+		defer deck.EndDeck()
+		rd = &deckrenderer{deck: deck}
+	}
 	if nf == 0 {
-		roadmap("", canvas)
+		roadmap("", rd)
 		return
 	}
 	for i := 0; i < nf; i++ {
-		roadmap(files[i], canvas)
+		roadmap(files[i], rd)
 	}
 }
 
 // roadmap reads and processes a roadmap XML file
-func roadmap(location string, canvas *gensvg.SVG) {
-	var f *os.File
-	var err error
-	f = os.Stdin
-	if len(location) > 0 {
-		f, err = os.Open(location)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "%v\n", err)
-			return
-		}
+func roadmap(location string, rd renderer) {
+	f, err := source.Open(location)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		problems++
+		return
 	}
-	readrm(f, canvas)
+	if len(location) == 0 {
+		location = "<stdin>"
+	}
+	readrm(f, location, rd)
 	f.Close()
 }
 
-// readrm reads in the roadmap struct
-func readrm(r io.Reader, canvas *gensvg.SVG) {
+// readrm reads in the roadmap struct, and draws or lints it
+func readrm(r io.Reader, name string, rd renderer) {
 	var rm Roadmap
 	switch *inputformat {
 	case "xml":
 		err := xml.NewDecoder(r).Decode(&rm)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "%v\n", err)
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			problems++
 			return
 		}
 	case "csv":
 		rm = readCSV(r)
 	}
-	drawrm(rm, canvas)
+	if *lintflag {
+		problems += lint(rm, name, os.Stdout)
+		return
+	}
+	if len(*schedflag) > 0 {
+		if err := writeschedule(rm, name, *schedflag, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			problems++
+		}
+		return
+	}
+	drawrm(rm, rd)
 	if len(*csvout) > 0 {
 		csvfile, err := os.Create(*csvout)
-		if err != err {
+		if err != nil {
 			fmt.Fprintf(os.Stderr, "%v\n", err)
 			return
 		}
@@ -184,92 +278,6 @@ func readrm(r io.Reader, canvas *gensvg.SVG) {
 	}
 }
 
-// rmcsv renders the roadmap data as CSV
-func rmcsv(r Roadmap, w io.Writer) {
-	fmt.Fprintln(w, "\"Category/Item\",Begin,Duration,Id,Connection")
-	for _, cat := range r.Category {
-		if cat.Vspace == "0" { // skip header categories
-			continue
-		}
-		fmt.Fprintf(w, ",,,,\n\"%s\",,,,\n", cat.Name) // category name, flanked by empty rows
-		for _, item := range cat.Item {
-			fmt.Fprintf(w, "\"%s\",%s,%s,%s", item.Text, item.Begin, item.Duration, item.Id)
-			if len(item.Dep) > 0 { // process deps
-				for _, d := range item.Dep {
-					fmt.Fprintf(w, ",%s", d.Dest)
-				}
-				fmt.Fprintf(w, "\n")
-			} else {
-				fmt.Fprintf(w, ",\n")
-			}
-		}
-	}
-}
-
-// readcsv reads a CSV version of a roadmap
-func readCSV(r io.Reader) Roadmap {
-	var (
-		cats  []Category
-		c     Category
-		it    Item
-		items []Item
-		rm    Roadmap
-	)
-
-	nc := -1
-	nr := 0
-	input := csv.NewReader(r)
-	for {
-		fields, csverr := input.Read()
-		if csverr == io.EOF {
-			break
-		}
-		if csverr != nil {
-			fmt.Fprintf(os.Stderr, "%v %v\n", csverr, fields)
-			continue
-		}
-		nr++
-		if nr == 1 {
-			continue
-		}
-		if len(fields) < 5 {
-			continue
-		}
-		if len(fields[0]) == 0 {
-			continue
-		}
-		if len(fields[0]) > 0 && len(fields[1]) == 0 && len(fields[2]) == 0 {
-			nc++
-			c.Name = fields[0]
-			c.Vspace = "45"
-			c.Itemheight = 40
-			cats = append(cats, c)
-			items = []Item{}
-			continue
-		}
-		it.Text = fields[0]
-		it.Begin = fields[1]
-		it.Duration = fields[2]
-		items = append(items, it)
-		cats[nc].Item = items
-	}
-	rp := strings.Split(*csvparam, ",")
-	if len(rp) == 3 {
-		rm.Title = rp[0]
-		rm.Begin, _ = strconv.ParseFloat(rp[1], 64)
-		rm.End, _ = strconv.ParseFloat(rp[2], 64)
-	}
-	rm.Scale = 12
-	rm.Catpercent = 15
-	rm.Vspace = 45
-	rm.Itemheight = 40
-	rm.Shape = "r"
-	rm.Fontname = "Calibri,sans-serif"
-	rm.Category = cats
-	dumprm(rm, os.Stderr)
-	return rm
-}
-
 // dumprm prints out the roadmap struct
 func dumprm(r Roadmap, w io.Writer) {
 	fmt.Fprintf(w, "title=%q begin=%v end=%v scale=%v catpercent=%v vspace=%v font=%v itemh=%v, shape=%q\n",
@@ -282,21 +290,21 @@ func dumprm(r Roadmap, w io.Writer) {
 	}
 }
 
-// readrm reads in the roadmap struct
-func drawrm(r Roadmap, canvas *gensvg.SVG) {
+// drawrm lays out the roadmap, and draws it with a renderer
+func drawrm(r Roadmap, rd renderer) {
 	var (
 		itemshape = "r"
 		itemalign = "middle"
 		itemcolor = "#BBBBBB88"
 		fontname  = "Calibri,sans-serif"
-		itemMargin, rightMargin, itemheight, itemyear, itempart,
+		itemMargin, rightMargin, itemheight,
 		itemvspace, catheight, cvspace, top, catx float64
 		bline = false
 	)
 
 	// Global roadmap attributes
-	beginyear := r.Begin
-	endyear := r.End
+	beginyear := r.Begin.Year
+	endyear := r.End.Year
 	yearscale := r.Scale
 	rvspace := r.Vspace
 	ritemheight := r.Itemheight
@@ -311,18 +319,54 @@ func drawrm(r Roadmap, canvas *gensvg.SVG) {
 	y := top
 	milestone := false
 
+	// the time axis, in years
+	tx := func(v float64) float64 { return fmap(v, beginyear, endyear, itemMargin, rightMargin) }
+
 	if *lalign == "end" {
 		catx = itemMargin - *lmargin
 	} else {
 		catx = *lmargin
 	}
 
-	canvas.Start(*width, *height)
-	canvas.Title(r.Title)
-	canvas.Rect(0, 0, *width, *height, "fill:"+*bgcolor)
+	// the critical path
+	var sched *schedule
+	if len(*critcolor) > 0 {
+		var err error
+		if sched, err = analyze(r); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			problems++
+		}
+	}
+
+	rd.start(r.Title, itemMargin, tloc, fontname)
 
-	canvas.Gstyle(catgstylefmt + fontname)
-	canvas.Text(itemMargin, tloc, r.Title, fmt.Sprintf(categoryfmt, *tfs))
+	// past time, and header rows
+	now := years(today)
+	if *past && now > beginyear {
+		rd.shade(itemMargin, top, tx(math.Min(now, endyear))-itemMargin, *height-top)
+	}
+	rows := r.Header
+	if len(*header) > 0 {
+		rows = *header
+	}
+	fy := r.Fystart
+	if *fystart > 0 {
+		fy = *fystart
+	}
+	if len(rows) > 0 && endyear > beginyear {
+		rowheight := *ifs + 8
+		for _, kind := range strings.Split(rows, ",") {
+			p, err := periods(strings.TrimSpace(kind), beginyear, endyear, fy, loc)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				continue
+			}
+			for _, c := range p {
+				rd.header(tx(c.begin), y, tx(c.end)-tx(c.begin), rowheight, c.label)
+			}
+			y += rowheight
+		}
+	}
 
 	// Process Categories
 	for cc, cat := range r.Category {
@@ -336,32 +380,23 @@ func drawrm(r Roadmap, canvas *gensvg.SVG) {
 		if len(cat.Name) > 0 {
 			label := strings.Split(cat.Name, "\\n")
 			ll := len(label)
-			if *boldcat {
-				canvas.Gstyle(boldfmt)
-			} else {
-				canvas.Gstyle(italicfmt)
-			}
-
 			if ll <= 1 {
 				ycatlabel = y + (catheight / 2) + *cfs/4
 			} else {
 				ycatlabel = y + ((float64(ll) * *cfs) / 2)
 			}
-			canvas.Textlines(catx, ycatlabel, label, *cfs, *cfs+2, "black", *lalign)
-			canvas.Gend()
+			rd.catlabel(catx, ycatlabel, *cfs+2, label)
 		}
 		if cat.Bline == "on" {
-			canvas.Line(itemMargin, y, rightMargin, y, borderfmt)
+			rd.border(itemMargin, y, rightMargin, y)
 		}
 
 		// Process Category descriptions
-		yd := ycatlabel + *cfs
-		canvas.Gstyle(fmt.Sprintf(catdescfmt, *ifs))
+		var cdlines []string
 		for _, cdi := range cat.Catdesc.Cditem {
-			canvas.Text(*lmargin, yd, cdi.Cdtext)
-			yd += *cfs + 2
+			cdlines = append(cdlines, cdi.Cdtext)
 		}
-		canvas.Gend()
+		rd.catdesc(*lmargin, ycatlabel+*cfs, *cfs+2, cdlines)
 
 		if len(cat.Vspace) == 0 {
 			cvspace = rvspace
@@ -371,16 +406,11 @@ func drawrm(r Roadmap, canvas *gensvg.SVG) {
 
 		// Process Items within categories
 		for ii, item := range cat.Item {
-			dt := strings.SplitN(item.Begin, "/", 2)
-			if len(dt) == 2 {
-				itemyear, _ = strconv.ParseFloat(dt[0], 64)
-				itempart, _ = strconv.ParseFloat(dt[1], 64)
-			} else {
+			itembegin, itemend, err := itemspan(item, yearscale)
+			if err != nil {
 				continue
 			}
 
-			itemduration, _ := strconv.ParseFloat(item.Duration, 64)
-
 			if item.Bline == "on" {
 				bline = true
 			} else {
@@ -410,13 +440,8 @@ func drawrm(r Roadmap, canvas *gensvg.SVG) {
 				}
 			}
 
-			if yearscale <= 0 {
-				yearscale = 1.0
-			}
-
-			itemfraction := (itempart - 1) / yearscale
-			itemx := fmap(itemyear+itemfraction, beginyear, endyear, itemMargin, rightMargin)
-			itemw := fmap(itemduration, 0, (endyear-beginyear)*yearscale, 0, rightMargin-itemMargin)
+			itemx := tx(itembegin)
+			itemw := fmap(itemend-itembegin, 0, endyear-beginyear, 0, rightMargin-itemMargin)
 
 			if len(cat.Shape) == 0 {
 				itemshape = r.Shape
@@ -439,13 +464,20 @@ func drawrm(r Roadmap, canvas *gensvg.SVG) {
 			if item.Vspace > 0 {
 				itemvspace = item.Vspace
 			}
-			drawitem(item.Text, itemx, y, itemw, itemheight, itemshape, itemcolor, itemalign, milestone, bline, canvas)
+			if bline {
+				rd.border(itemx+itemw, y, itemx+itemw, *height)
+			}
+			rd.item(item.Text, itemx, y, itemw, itemheight, itemshape, itemcolor, itemalign, milestone)
+			if sched != nil && sched.critical(&r.Category[cc].Item[ii]) {
+				rd.highlight(itemx, y, itemw, itemheight)
+			}
 
 			if len(item.Desc) > 0 {
+				lines := wrap(item.Desc, *twrap)
 				if *descend {
-					textwrap(itemx+itemw, y+*ifs, *twrap, *ifs, *ifs+2, item.Desc, fontname, "start", *descolor, 1.0, canvas)
+					rd.itemdesc(itemx+itemw, y+*ifs, *ifs+2, lines, fontname, "start")
 				} else {
-					textwrap(itemx-5, y+*ifs, *twrap, *ifs, *ifs+2, item.Desc, fontname, "end", *descolor, 1.0, canvas)
+					rd.itemdesc(itemx-5, y+*ifs, *ifs+2, lines, fontname, "end")
 				}
 			}
 
@@ -467,47 +499,53 @@ func drawrm(r Roadmap, canvas *gensvg.SVG) {
 		}
 
 		if *catborder && cc > 1 {
-			canvas.Line(itemMargin, y-rvspace, rightMargin, y-rvspace, borderfmt)
+			rd.border(itemMargin, y-rvspace, rightMargin, y-rvspace)
 		}
 
 	}
 
+	// today
+	if len(*todayflag) > 0 && now >= beginyear && now <= endyear {
+		rd.marker(tx(now), top, *height, loc.FormatTime(today, "Jan 2"))
+	}
+
 	// Process dependencies
-	canvas.Gstyle(fmt.Sprintf(depfmt, *concolor))
-	for _, c := range r.Category {
-		for _, i := range c.Item {
-			for _, d := range i.Dep {
-				connect(i, d, r.Category, canvas)
+	rd.startlinks()
+	for ci := range r.Category {
+		for ii := range r.Category[ci].Item {
+			item := &r.Category[ci].Item[ii]
+			for _, d := range item.Dep {
+				connect(item, d, r.Category, sched, rd)
 			}
 		}
 	}
-	canvas.Gend()
-
-	canvas.Gend()
+	rd.endlinks()
 
 	// borders
 	if *leftborder {
-		canvas.Line(itemMargin, top, itemMargin, *height, borderfmt)
+		rd.border(itemMargin, top, itemMargin, *height)
 	}
 	if *topborder {
-		canvas.Line(itemMargin, top, rightMargin, top, borderfmt)
+		rd.border(itemMargin, top, rightMargin, top)
 	}
 	if *botborder {
-		canvas.Line(itemMargin, *height, rightMargin, *height, borderfmt)
+		rd.border(itemMargin, *height, rightMargin, *height)
 	}
 	if *rightborder {
-		canvas.Line(rightMargin, top, rightMargin, *height, borderfmt)
+		rd.border(rightMargin, top, rightMargin, *height)
 	}
-	canvas.End()
+	rd.end()
 }
 
-// connect matches destinations to make connections
-func connect(item Item, d Dep, cats []Category, canvas *gensvg.SVG) {
+// connect matches destinations to make connections,
+// marking those on the critical path of a schedule
+func connect(item *Item, d Dep, cats []Category, sched *schedule, rd renderer) {
 	var curvex, curvey float64
 	fmt.Sscanf(*curves, "%d,%d", &curvex, &curvey)
 
-	for _, c := range cats {
-		for _, i := range c.Item {
+	for ci := range cats {
+		for ii := range cats[ci].Item {
+			i := &cats[ci].Item[ii]
 			if (d.Dest == i.Id) && len(d.Dest) > 0 {
 				bx := item.X + item.W*d.BPct
 				by := item.Y + item.H/2
@@ -515,12 +553,7 @@ func connect(item Item, d Dep, cats []Category, canvas *gensvg.SVG) {
 				ey := i.Y + i.H/2
 				cx := ex + curvex
 				cy := ey + curvey
-				canvas.Qbez(bx, by, cx, cy, ex, ey)
-				canvas.Circle(ex, ey, 4, fmt.Sprintf(ccfmt, *concolor))
-				if len(d.Desc) > 0 {
-					canvas.Text(ex, ey+item.H+5, d.Desc,
-						fmt.Sprintf(connectfmt, *descolor))
-				}
+				rd.link(bx, by, cx, cy, ex, ey, d.Desc, ey+item.H+5, sched != nil && sched.criticallink(item, i))
 			}
 		}
 	}
@@ -531,93 +564,32 @@ func whitespace(r rune) bool {
 	return r == ' ' || r == '\n' || r == '\t'
 }
 
-// textwrap draws text at location, wrapping at the specified width
-func textwrap(x, y, w, fs, leading float64, s, font, align, color string, opacity float64, canvas *gensvg.SVG) {
-	canvas.Gstyle(fmt.Sprintf(twrapfmt, align, opacity, color, font, fs))
-	words := strings.FieldsFunc(s, whitespace)
-	xp := x
-	yp := y
+// wrap breaks text into lines, each ending after the word that takes it past w characters
+func wrap(s string, w float64) []string {
+	var lines []string
 	var line string
-	for _, s := range words {
+	for _, s := range strings.FieldsFunc(s, whitespace) {
 		line += s + " "
 		if float64(len(line)) > w {
-			canvas.Text(xp, yp, line)
-			yp += leading
+			lines = append(lines, line)
 			line = ""
 		}
 	}
 	if len(line) > 0 {
-		canvas.Text(xp, yp, line)
+		lines = append(lines, line)
 	}
-	canvas.Gend()
+	return lines
 }
 
-// drawitem renders roadmap items
-func drawitem(s string, x, y, w, h float64, shape, color, align string, milestone, bline bool, canvas *gensvg.SVG) {
-	var textfill string
-	fc := fmt.Sprintf(strokefmt, *bgcolor, hexstyle(color))
-
-	if bline {
-		canvas.Line(x+w, y, x+w, *height, borderfmt)
-	}
-
-	if len(s) > 0 {
-		switch shape {
-		case "r":
-			canvas.Rect(x, y, w, h, fc)
-		case "rr":
-			canvas.Roundrect(x, y, w, h, 5, 5, fc)
-		case "e":
-			canvas.Ellipse(x+(w/2), y+(h/2), w/2, h/2, fc)
-		case "c":
-			canvas.Circle(x+(w/2), y+(h/2), h/2, fc)
-		case "a":
-			arrow(x, y, w, h, h/2, fc, canvas)
-		case "l":
-			yl := (y + (h / 2)) + ((*ifs / 4) - (*ifs / 3))
-			canvas.Line(x, yl, x+w, yl, fmt.Sprintf(itemlinefmt, color, *ifs))
-		default:
-			canvas.Rect(x, y, w, h, fc)
-		}
-	} else {
-		canvas.Rect(x, y, w, h, fc)
-	}
-
+// textcolor returns the color of text drawn over an item color
+func textcolor(color string) string {
 	red, green, blue, alpha := colorcomp(color)
 	_, _, v := rgbtohsb(red, green, blue)
 
 	if v <= 100.0 && v > 70.0 || alpha < 127 {
-		textfill = "black"
-	} else {
-		textfill = "white"
-	}
-
-	if milestone {
-		s += " \u2605"
+		return "black"
 	}
-	tx := x
-	switch align {
-	case "start":
-		tx += 1.0 // 5
-	case "middle":
-		tx += (w / 2)
-	case "end":
-		tx += (w)
-	default:
-		tx += (w / 2)
-	}
-	canvas.Text(tx, (y+(h/2))+(*ifs/4), s, fmt.Sprintf(itemtextfmt, align, textfill, *ifs))
-
-}
-
-// arrow makes an arrow shape
-func arrow(x float64, y float64, w float64, h float64, ah float64, color string, canvas *gensvg.SVG) {
-	end := x + w
-	bot := y + h
-	ap := end - ah
-	var xp = []float64{x, ap, end, ap, x}
-	var yp = []float64{y, y, y + (h / 2), bot, bot}
-	canvas.Polyline(xp, yp, color)
+	return "white"
 }
 
 // rgbtohsb converts an RGB triple to HSB
@@ -688,16 +660,6 @@ func colorcomp(s string) (red, green, blue, alpha float64) {
 	return red, green, blue, alpha
 }
 
-// wordstack takes a slice of string into a stack of words
-func wordstack(x, y, fs float64, s []string, style string, canvas *gensvg.SVG) {
-	ls := fs + (fs / 2)
-	y -= ls
-	for i := len(s); i > 0; i-- {
-		canvas.Text(x, y, s[i-1], style)
-		y -= ls
-	}
-}
-
 // fmap maps ranges
 func fmap(value, low1, high1, low2, high2 float64) float64 {
 	return low2 + (high2-low2)*(value-low1)/(high1-low1)
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// node is an item in the dependency graph. Times are in years: the
+// planned begin and end, and the earliest and latest starts and finishes.
+type node struct {
+	item       *Item
+	cat        string
+	begin, end float64
+	es, ef     float64
+	ls, lf     float64
+	pred, succ []*node
+	critical   bool
+}
+
+// slack is how far an item may slip without delaying the finish
+func (n *node) slack() float64 {
+	return n.ls - n.es
+}
+
+// schedule is the analysis of a roadmap's dependencies: its items in
+// dependency order, and when the last of them finishes
+type schedule struct {
+	nodes  []*node
+	finish float64
+	byitem map[*Item]*node
+}
+
+// analyze builds the graph of the items connected by dependencies, an
+// item preceding those it connects to, and finds the critical path.
+// Items start no earlier than planned, nor before their predecessors
+// finish; the critical items are those with no slack.
+func analyze(r Roadmap) (*schedule, error) {
+	s := &schedule{byitem: map[*Item]*node{}}
+	ids := map[string]*Item{}
+	for ci := range r.Category {
+		for ii := range r.Category[ci].Item {
+			item := &r.Category[ci].Item[ii]
+			if _, ok := ids[item.Id]; len(item.Id) > 0 && !ok {
+				ids[item.Id] = item
+			}
+		}
+	}
+	add := func(item *Item, cat string) *node {
+		if n, ok := s.byitem[item]; ok {
+			return n
+		}
+		b, e, err := itemspan(*item, r.Scale)
+		if err != nil || e < b {
+			return nil
+		}
+		n := &node{item: item, cat: cat, begin: b, end: e}
+		s.byitem[item] = n
+		return n
+	}
+	cat := map[*Item]string{}
+	for ci, c := range r.Category {
+		for ii := range c.Item {
+			cat[&r.Category[ci].Item[ii]] = catname(c, ci)
+		}
+	}
+
+	// the graph, in the order of the roadmap
+	var all []*node
+	for ci := range r.Category {
+		for ii := range r.Category[ci].Item {
+			item := &r.Category[ci].Item[ii]
+			for _, d := range item.Dep {
+				dest, ok := ids[d.Dest]
+				if !ok {
+					continue
+				}
+				from, to := add(item, cat[item]), add(dest, cat[dest])
+				if from == nil || to == nil {
+					continue
+				}
+				from.succ = append(from.succ, to)
+				to.pred = append(to.pred, from)
+			}
+		}
+	}
+	for ci := range r.Category {
+		for ii := range r.Category[ci].Item {
+			if n, ok := s.byitem[&r.Category[ci].Item[ii]]; ok {
+				all = append(all, n)
+			}
+		}
+	}
+
+	// dependency order
+	waiting := map[*node]int{}
+	var ready []*node
+	for _, n := range all {
+		waiting[n] = len(n.pred)
+		if len(n.pred) == 0 {
+			ready = append(ready, n)
+		}
+	}
+	for len(ready) > 0 {
+		n := ready[0]
+		ready = ready[1:]
+		s.nodes = append(s.nodes, n)
+		for _, m := range n.succ {
+			waiting[m]--
+			if waiting[m] == 0 {
+				ready = append(ready, m)
+			}
+		}
+	}
+	if len(s.nodes) < len(all) {
+		return nil, fmt.Errorf("the dependencies have a cycle (see -lint)")
+	}
+	if len(s.nodes) == 0 {
+		return s, nil
+	}
+
+	// earliest starts, forward
+	s.finish = math.Inf(-1)
+	for _, n := range s.nodes {
+		n.es = n.begin
+		for _, p := range n.pred {
+			n.es = math.Max(n.es, p.ef)
+		}
+		n.ef = n.es + (n.end - n.begin)
+		s.finish = math.Max(s.finish, n.ef)
+	}
+	// latest starts, backward
+	for i := len(s.nodes) - 1; i >= 0; i-- {
+		n := s.nodes[i]
+		n.lf = s.finish
+		for _, m := range n.succ {
+			n.lf = math.Min(n.lf, m.ls)
+		}
+		n.ls = n.lf - (n.end - n.begin)
+		n.critical = n.slack() < epsilon
+	}
+	return s, nil
+}
+
+// critical reports whether an item is on the critical path
+func (s *schedule) critical(item *Item) bool {
+	n, ok := s.byitem[item]
+	return ok && n.critical
+}
+
+// criticallink reports whether a dependency is on the critical path:
+// both items are critical, and one follows the other without a gap
+func (s *schedule) criticallink(from, to *Item) bool {
+	a, b := s.byitem[from], s.byitem[to]
+	return a != nil && b != nil && a.critical && b.critical && math.Abs(b.es-a.ef) < epsilon
+}
+
+// days converts years to days
+func days(v float64) float64 {
+	return math.Round(v*365.25*10) / 10
+}
+
+// slip is an item of the schedule report, as written with -schedule json
+type slip struct {
+	Category string  `json:"category"`
+	Item     string  `json:"item"`
+	Id       string  `json:"id,omitempty"`
+	Earliest string  `json:"earliest_start"`
+	Latest   string  `json:"latest_start"`
+	Slack    float64 `json:"slack_days"`
+	Critical bool    `json:"critical"`
+}
+
+// report is the schedule of a roadmap, as written with -schedule json
+type report struct {
+	File     string   `json:"file"`
+	Finish   string   `json:"finish,omitempty"`
+	Critical []string `json:"critical_path"`
+	Items    []slip   `json:"items"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// writeschedule reports when the items connected by dependencies may
+// start, and which of them would delay the finish if they slipped
+func writeschedule(r Roadmap, name, format string, w io.Writer) error {
+	rep := report{File: name, Critical: []string{}, Items: []slip{}}
+	s, err := analyze(r)
+	if err != nil {
+		if format == "json" {
+			rep.Error = err.Error()
+			json.NewEncoder(w).Encode(rep)
+		}
+		return err
+	}
+	if len(s.nodes) > 0 {
+		rep.Finish = isotime(s.finish)
+	}
+	for _, n := range s.nodes {
+		sl := slip{
+			Category: n.cat,
+			Item:     itemname(*n.item),
+			Id:       n.item.Id,
+			Earliest: isotime(n.es),
+			Latest:   isotime(n.ls),
+			Slack:    days(n.slack()),
+			Critical: n.critical,
+		}
+		rep.Items = append(rep.Items, sl)
+		if n.critical {
+			rep.Critical = append(rep.Critical, sl.Item)
+		}
+	}
+	if format == "json" {
+		return json.NewEncoder(w).Encode(rep)
+	}
+
+	if len(s.nodes) == 0 {
+		fmt.Fprintf(w, "%s: no items are connected by dependencies\n", name)
+		return nil
+	}
+	fmt.Fprintf(w, "%s: finishes %s; critical path: %s\n", name, rep.Finish, strings.Join(rep.Critical, ", "))
+	cw := 0
+	for _, sl := range rep.Items {
+		if l := len(sl.Category + " / " + sl.Item); l > cw {
+			cw = l
+		}
+	}
+	for _, sl := range rep.Items {
+		mark := ""
+		if sl.Critical {
+			mark = "  critical"
+		}
+		fmt.Fprintf(w, "  %-*s  earliest %s  latest %s  slack %6.1f days%s\n",
+			cw, sl.Category+" / "+sl.Item, sl.Earliest, sl.Latest, sl.Slack, mark)
+	}
+	return nil
+}
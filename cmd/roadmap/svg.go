@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ajstarks/gensvg"
+)
+
+// renderer draws a roadmap laid out by drawrm. Coordinates and sizes
+// are in pixels, with y increasing down the canvas.
+type renderer interface {
+	start(title string, x, y float64, font string)  // the canvas, its background and the title
+	catlabel(x, y, leading float64, label []string) // a category label, one or more lines
+	header(x, y, w, h float64, label string)        // a cell of a header row
+	shade(x, y, w, h float64)                       // shading for past time
+	marker(x, y1, y2 float64, label string)         // the today line
+	catdesc(x, y, leading float64, lines []string)  // a category description
+	item(s string, x, y, w, h float64, shape, color, align string, milestone bool)
+	itemdesc(x, y, leading float64, lines []string, font, align string) // an item description
+	startlinks()                                                        // begin the dependency connections
+	highlight(x, y, w, h float64)                                       // an item on the critical path
+	link(bx, by, cx, cy, ex, ey float64, desc string, descy float64, critical bool)
+	endlinks()
+	border(x1, y1, x2, y2 float64) // a border or boundary line
+	end()
+}
+
+// svgrenderer draws roadmaps as SVG
+type svgrenderer struct {
+	canvas *gensvg.SVG
+}
+
+// start begins the SVG document, and the group holding its text
+func (s *svgrenderer) start(title string, x, y float64, font string) {
+	s.canvas.Start(*width, *height)
+	s.canvas.Title(title)
+	s.canvas.Rect(0, 0, *width, *height, "fill:"+*bgcolor)
+	s.canvas.Gstyle(catgstylefmt + font)
+	s.canvas.Text(x, y, title, fmt.Sprintf(categoryfmt, *tfs))
+}
+
+// header draws a cell of a header row, labelled if the label fits
+func (s *svgrenderer) header(x, y, w, h float64, label string) {
+	s.canvas.Rect(x, y, w, h, headerfmt)
+	if fits(label, w) {
+		s.canvas.Text(x+w/2, y+h/2+*ifs/3, label, fmt.Sprintf(headtextfmt, *ifs))
+	}
+}
+
+// shade shades past time
+func (s *svgrenderer) shade(x, y, w, h float64) {
+	s.canvas.Rect(x, y, w, h, shadefmt)
+}
+
+// marker draws the today line, labelled above
+func (s *svgrenderer) marker(x, y1, y2 float64, label string) {
+	s.canvas.Line(x, y1, x, y2, fmt.Sprintf(markerfmt, markercolor))
+	s.canvas.Text(x, y1-2, label, fmt.Sprintf(markertxtfmt, markercolor, *ifs*0.8))
+}
+
+// catlabel draws a category label
+func (s *svgrenderer) catlabel(x, y, leading float64, label []string) {
+	if *boldcat {
+		s.canvas.Gstyle(boldfmt)
+	} else {
+		s.canvas.Gstyle(italicfmt)
+	}
+	s.canvas.Textlines(x, y, label, *cfs, leading, "black", *lalign)
+	s.canvas.Gend()
+}
+
+// catdesc draws a category description
+func (s *svgrenderer) catdesc(x, y, leading float64, lines []string) {
+	s.canvas.Gstyle(fmt.Sprintf(catdescfmt, *ifs))
+	for _, t := range lines {
+		s.canvas.Text(x, y, t)
+		y += leading
+	}
+	s.canvas.Gend()
+}
+
+// item draws a roadmap item
+func (s *svgrenderer) item(t string, x, y, w, h float64, shape, color, align string, milestone bool) {
+	canvas := s.canvas
+	fc := fmt.Sprintf(strokefmt, *bgcolor, hexstyle(color))
+	if len(t) > 0 {
+		switch shape {
+		case "r":
+			canvas.Rect(x, y, w, h, fc)
+		case "rr":
+			canvas.Roundrect(x, y, w, h, 5, 5, fc)
+		case "e":
+			canvas.Ellipse(x+(w/2), y+(h/2), w/2, h/2, fc)
+		case "c":
+			canvas.Circle(x+(w/2), y+(h/2), h/2, fc)
+		case "a":
+			arrow(x, y, w, h, h/2, fc, canvas)
+		case "l":
+			yl := (y + (h / 2)) + ((*ifs / 4) - (*ifs / 3))
+			canvas.Line(x, yl, x+w, yl, fmt.Sprintf(itemlinefmt, color, *ifs))
+		default:
+			canvas.Rect(x, y, w, h, fc)
+		}
+	} else {
+		canvas.Rect(x, y, w, h, fc)
+	}
+	if milestone {
+		t += " \u2605"
+	}
+	tx := x
+	switch align {
+	case "start":
+		tx += 1.0 // 5
+	case "middle":
+		tx += (w / 2)
+	case "end":
+		tx += (w)
+	default:
+		tx += (w / 2)
+	}
+	canvas.Text(tx, (y+(h/2))+(*ifs/4), t, fmt.Sprintf(itemtextfmt, align, textcolor(color), *ifs))
+}
+
+// itemdesc draws the wrapped lines of an item description
+func (s *svgrenderer) itemdesc(x, y, leading float64, lines []string, font, align string) {
+	s.canvas.Gstyle(fmt.Sprintf(twrapfmt, align, 1.0, *descolor, font, *ifs))
+	for _, t := range lines {
+		s.canvas.Text(x, y, t)
+		y += leading
+	}
+	s.canvas.Gend()
+}
+
+// startlinks begins the group of connections
+func (s *svgrenderer) startlinks() {
+	s.canvas.Gstyle(fmt.Sprintf(depfmt, *concolor))
+}
+
+// highlight outlines an item on the critical path
+func (s *svgrenderer) highlight(x, y, w, h float64) {
+	s.canvas.Rect(x, y, w, h, fmt.Sprintf(critfmt, *critcolor))
+}
+
+// link connects items with a curve, ending in a dot; links on the
+// critical path are solid
+func (s *svgrenderer) link(bx, by, cx, cy, ex, ey float64, desc string, descy float64, critical bool) {
+	if critical {
+		s.canvas.Qbez(bx, by, cx, cy, ex, ey, fmt.Sprintf(critlinkfmt, *critcolor))
+	} else {
+		s.canvas.Qbez(bx, by, cx, cy, ex, ey)
+	}
+	s.canvas.Circle(ex, ey, 4, fmt.Sprintf(ccfmt, *concolor))
+	if len(desc) > 0 {
+		s.canvas.Text(ex, descy, desc, fmt.Sprintf(connectfmt, *descolor))
+	}
+}
+
+// endlinks ends the group of connections, and the text group begun by start
+func (s *svgrenderer) endlinks() {
+	s.canvas.Gend()
+	s.canvas.Gend()
+}
+
+// border draws a border line
+func (s *svgrenderer) border(x1, y1, x2, y2 float64) {
+	s.canvas.Line(x1, y1, x2, y2, borderfmt)
+}
+
+// end ends the SVG document
+func (s *svgrenderer) end() {
+	s.canvas.End()
+}
+
+// arrow makes an arrow shape
+func arrow(x float64, y float64, w float64, h float64, ah float64, color string, canvas *gensvg.SVG) {
+	end := x + w
+	bot := y + h
+	ap := end - ah
+	var xp = []float64{x, ap, end, ap, x}
+	var yp = []float64{y, y, y + (h / 2), bot, bot}
+	canvas.Polyline(xp, yp, color)
+}
+
+// wordstack takes a slice of string into a stack of words
+func wordstack(x, y, fs float64, s []string, style string, canvas *gensvg.SVG) {
+	ls := fs + (fs / 2)
+	y -= ls
+	for i := len(s); i > 0; i-- {
+		canvas.Text(x, y, s[i-1], style)
+		y -= ls
+	}
+}
@@ -10,7 +10,12 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/ajstarks/deck/generate"
+	"github.com/ajstarks/utils/locale"
+	"github.com/ajstarks/utils/markup"
+	"github.com/ajstarks/utils/scale"
+	"github.com/ajstarks/utils/settings"
+	"github.com/ajstarks/utils/source"
+	"github.com/ajstarks/utils/theme"
 )
 
 type nameval struct {
@@ -21,7 +26,22 @@ type nameval struct {
 
 type options struct {
 	min, max, left, right, bottom, top, textsize, linewidth float64
-	color, vcolor                                           string
+	color, vcolor, scale                                    string
+	theme                                                   theme.Theme
+	nf                                                      locale.Formatter
+}
+
+// ticks is the number of axis ticks to aim for
+const ticks = 5
+
+// defaults is the look without a theme; text sizes are relative
+// to the label size, which -textsize sets
+var defaults = theme.Theme{
+	Accent: []string{"steelblue", "maroon"},
+	Grid:   "black",
+	Title:  theme.Font{Name: "sans", Size: 3},
+	Label:  theme.Font{Name: "sans", Size: 1.5},
+	Value:  theme.Font{Name: "sans", Size: 1.125},
 }
 
 var xmlmap = strings.NewReplacer(
@@ -82,21 +102,37 @@ func readData(r io.ReadCloser) ([]nameval, string, float64, float64, error) {
 
 }
 
-// vmap maps one range into another
-func vmap(value float64, low1 float64, high1 float64, low2 float64, high2 float64) float64 {
-	return low2 + (high2-low2)*(value-low1)/(high1-low1)
+// yscale makes the value scale. Unless set, the maximum is that of the data,
+// rounded out to a nice value, and log scales begin at the smallest value.
+func yscale(opts options, datamin, datamax float64) (scale.Scale, error) {
+	lo, hi := opts.min, opts.max
+	if opts.scale == "log" && lo <= 0 {
+		lo = datamin
+	}
+	if hi <= 0 {
+		hi = datamax
+	}
+	if hi <= lo {
+		hi = lo + 1
+	}
+	s, err := scale.New(opts.scale, lo, hi, opts.bottom, opts.top)
+	if err != nil {
+		return nil, err
+	}
+	if opts.max <= 0 {
+		s.Nice(ticks)
+	}
+	return s, nil
 }
 
-func slopechart(deck *generate.Deck, opts options, r io.ReadCloser) error {
-	data, title, _, _, err := readData(r)
+func slopechart(deck markup.Drawer, opts options, r io.ReadCloser) error {
+	data, title, datamin, datamax, err := readData(r)
 	if err != nil {
 		return err
 	}
 	if len(data) < 2 {
 		return fmt.Errorf("need at least two data points")
 	}
-	min := opts.min
-	max := opts.max
 	left := opts.left
 	right := opts.right
 	top := opts.top
@@ -106,14 +142,26 @@ func slopechart(deck *generate.Deck, opts options, r io.ReadCloser) error {
 	textsize := opts.textsize
 	linewidth := opts.linewidth
 	lw := linewidth / 2
+	th := opts.theme
 
-	lsize := textsize * 0.75
+	k := textsize / th.Label.Size
+	lsize := th.Value.Size * k
 	tsize := textsize * 1.5
 	w := right - left
 	h := top - bottom
 	if len(title) > 0 {
-		hsize := textsize * 2
-		deck.Text(left, top+10, title, "sans", hsize, "")
+		deck.Text(left, top+10, title, th.Title.Name, th.Title.Size*k, "")
+	}
+
+	ys, err := yscale(opts, datamin, datamax)
+	if err != nil {
+		return err
+	}
+	tk := ys.Ticks(ticks)
+	last := tk[len(tk)-1]
+	maxlabel := opts.nf.FormatTick(last.Value, last.Step)
+	if opts.max > 0 {
+		maxlabel = opts.nf.Format(opts.max)
 	}
 
 	hskip := w * .60
@@ -122,22 +170,22 @@ func slopechart(deck *generate.Deck, opts options, r io.ReadCloser) error {
 	x2 := right
 	for i := 0; i < len(data)-1; i += 2 {
 		if len(data[i].label) > 0 {
-			deck.TextMid(x1+(w/2), top+3, data[i].label, "sans", tsize, "")
+			deck.TextMid(x1+(w/2), top+3, data[i].label, th.Label.Name, tsize, "")
 		}
 		v1 := data[i].value
 		v2 := data[i+1].value
-		v1y := vmap(v1, min, max, bottom, top)
-		v2y := vmap(v2, min, max, bottom, top)
-		deck.Line(x1, bottom, x1, top, lw, "black")
-		deck.Line(x2, bottom, x2, top, lw, "black")
+		v1y := ys.Map(v1) - opts.bottom + bottom
+		v2y := ys.Map(v2) - opts.bottom + bottom
+		deck.Line(x1, bottom, x1, top, lw, th.Grid)
+		deck.Line(x2, bottom, x2, top, lw, th.Grid)
 		deck.Circle(x1, v1y, textsize, color)
 		deck.Circle(x2, v2y, textsize, color)
 		deck.Line(x1, v1y, x2, v2y, linewidth, color)
-		deck.TextMid(x1, bottom-2, data[i].name, "sans", textsize, "")
-		deck.TextMid(x2, bottom-2, data[i+1].name, "sans", textsize, "")
-		deck.TextEnd(x1-1, top, fmt.Sprintf("%g", max), "sans", lsize, "")
-		deck.TextEnd(x1-1, v1y, fmt.Sprintf("%g", v1), "sans", lsize, vcolor)
-		deck.Text(x2+1, v2y, fmt.Sprintf("%g", v2), "sans", lsize, vcolor)
+		deck.TextMid(x1, bottom-2, data[i].name, th.Label.Name, textsize, "")
+		deck.TextMid(x2, bottom-2, data[i+1].name, th.Label.Name, textsize, "")
+		deck.TextEnd(x1-1, top, maxlabel, th.Value.Name, lsize, "")
+		deck.TextEnd(x1-1, v1y, opts.nf.Format(v1), th.Value.Name, lsize, vcolor)
+		deck.Text(x2+1, v2y, opts.nf.Format(v2), th.Value.Name, lsize, vcolor)
 		x1 += w + hskip
 		x2 += w + hskip
 		if x2 > 100 {
@@ -158,13 +206,34 @@ func main() {
 	top := flag.Float64("top", 60, "top")
 	color := flag.String("color", "steelblue", "color")
 	vcolor := flag.String("vcolor", "maroon", "value color")
-	max := flag.Float64("max", 100, "max value")
+	min := flag.Float64("min", 0, "min value")
+	max := flag.Float64("max", 0, "max value (0 for the data maximum, rounded out)")
+	kind := flag.String("scale", "linear", "value scale (linear, log, symlog, sqrt)")
 	textsize := flag.Float64("textsize", 1.5, "text size")
 	linewidth := flag.Float64("linewidth", 0.2, "line width")
-	flag.Parse()
+	style := flag.String("style", "deck", "output style (deck, decksh, svg)")
+	themename := flag.String("theme", "", "theme (light, dark, print, or a JSON file)")
+	localename := flag.String("locale", "", "locale for numbers (as en-US, de-DE or fr)")
+	numfmt := flag.String("numfmt", "plain", "number format (plain, group, si or percent, optionally :decimals)")
+	source.Flags()
+	settings.Parse()
+
+	th, err := theme.Choose(*themename, defaults)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	nf, err := locale.NewFormatter(*localename, *numfmt)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	theme.Use(color, "color", th.Color(0))
+	theme.Use(vcolor, "vcolor", th.Color(1))
+	theme.Use(textsize, "textsize", th.Label.Size)
 
 	opts := options{
-		min:       0,
+		min:       *min,
 		max:       *max,
 		left:      *left,
 		right:     *right,
@@ -174,12 +243,24 @@ func main() {
 		textsize:  *textsize,
 		color:     *color,
 		vcolor:    *vcolor,
+		scale:     *kind,
+		theme:     th,
+		nf:        nf,
 	}
 
-	deck := generate.NewSlides(os.Stdout, 0, 0)
+	r, err := source.Open(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	deck, err := markup.New(os.Stdout, *style, 0, 0)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
 	deck.StartDeck()
-	deck.StartSlide()
-	if err := slopechart(deck, opts, os.Stdin); err != nil {
+	deck.StartSlide(th.SlideColors()...)
+	if err := slopechart(deck, opts, r); err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
 	}
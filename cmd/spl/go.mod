@@ -1,3 +1,9 @@
 module github.com/ajstarks/utils/cmd/spl
 
 go 1.22.0
+
+require github.com/ajstarks/utils/imagescan v0.0.0
+
+require golang.org/x/image v0.18.0 // indirect
+
+replace github.com/ajstarks/utils/imagescan => ../../imagescan
@@ -2,13 +2,13 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
-	"image"
-	_ "image/gif"
-	_ "image/jpeg"
-	_ "image/png"
+	"io/fs"
 	"os"
+
+	"github.com/ajstarks/utils/imagescan"
 )
 
 const (
@@ -114,26 +114,21 @@ func placepicsw(c Canvas, pics []Picture, targetpct float64) {
 	fmt.Println(eslide)
 }
 
+// piclist reads the sizes of the images in the files and directories named;
+// files that are not images are skipped
 func piclist(filelist []string) []Picture {
-	var pic Picture
 	p := []Picture{}
-	for _, imagefile := range filelist {
-		imf, err := os.Open(imagefile)
-		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			continue
+	imagescan.Scan(filelist, func(im imagescan.Image) {
+		var perr *fs.PathError
+		if errors.As(im.Err, &perr) {
+			fmt.Fprintln(os.Stderr, im.Err)
+			return
 		}
-		im, _, err := image.DecodeConfig(imf)
-		if err != nil {
-			imf.Close()
-			continue
+		if im.Err != nil {
+			return
 		}
-		pic.width = im.Width
-		pic.height = im.Height
-		pic.name = imagefile
-		p = append(p, pic)
-		imf.Close()
-	}
+		p = append(p, Picture{width: im.Width, height: im.Height, name: im.Name})
+	})
 	return p
 }
 
@@ -196,7 +191,7 @@ func msingle(c Canvas, pics []Picture) {
 			fmt.Printf(snamefmt, p.name)
 		}
 		fmt.Printf(simgfmt, p.x, p.y, pw, ph, p.name)
-		fmt.Printf(eslide)
+		fmt.Println(eslide)
 	}
 }
 
@@ -212,6 +207,7 @@ func main() {
 	all := flag.Int("a", 0, "all n")
 	showname := flag.Bool("showname", false, "show name")
 	bgcolor := flag.String("bg", "white", "background color")
+	imagescan.Flags()
 	flag.Parse()
 
 	pics := piclist(flag.Args())
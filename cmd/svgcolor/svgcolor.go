@@ -1,6 +1,8 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
 	"fmt"
 	"os"
 )
@@ -160,9 +162,30 @@ var colormap = map[string]RGB{
 	"yellowgreen":          {154, 205, 50},
 }
 
+// color is a named color, as written with -json
+type color struct {
+	Name  string `json:"name"`
+	Red   int    `json:"red"`
+	Green int    `json:"green"`
+	Blue  int    `json:"blue"`
+	Hex   string `json:"hex"`
+	Error string `json:"error,omitempty"`
+}
+
 func main() {
-	for _, c := range os.Args[1:] {
+	jsonout := flag.Bool("json", false, "write JSON Lines")
+	flag.Parse()
+	enc := json.NewEncoder(os.Stdout)
+	for _, c := range flag.Args() {
 		v, ok := colormap[c]
+		if *jsonout {
+			if ok {
+				enc.Encode(color{Name: c, Red: v.red, Green: v.green, Blue: v.blue, Hex: fmt.Sprintf("#%02x%02x%02x", v.red, v.green, v.blue)})
+			} else {
+				enc.Encode(color{Name: c, Error: "unknown color"})
+			}
+			continue
+		}
 		if ok {
 			fmt.Printf("%s rgb(%d,%d,%d) #%02x%02x%02x\n", c, v.red, v.green, v.blue, v.red, v.green, v.blue)
 		}
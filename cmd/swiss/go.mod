@@ -1,5 +1,11 @@
 module github.com/ajstarks/utils/cmd/swiss
 
-go 1.16
+go 1.21.6
 
-require github.com/ajstarks/deck/generate v0.0.0-20210223212949-8bd01c798494
+require github.com/ajstarks/utils/markup v0.0.0
+
+require github.com/ajstarks/utils/readpalette v0.0.0 // indirect
+
+replace github.com/ajstarks/utils/markup => ../../markup
+
+replace github.com/ajstarks/utils/readpalette => ../../readpalette
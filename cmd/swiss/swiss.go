@@ -3,11 +3,13 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"math"
 	"os"
 	"time"
 
-	"github.com/ajstarks/deck/generate"
+	"github.com/ajstarks/utils/markup"
 )
 
 var hrangles = [12]float64{
@@ -41,7 +43,7 @@ func polar(cx, cy, r, degrees float64) (float64, float64) {
 }
 
 // clock draws a clock face with hour and minute markers
-func clock(deck *generate.Deck, x, y, r float64) {
+func clock(deck markup.Drawer, x, y, r float64) {
 	n := 0
 	var r2, s2 float64
 
@@ -82,7 +84,7 @@ func oppangle(a float64) float64 {
 }
 
 // drawtime draws a clock face with  hour, minute and second hands
-func drawtime(deck *generate.Deck, x, y, r float64, h, m, s int) {
+func drawtime(deck markup.Drawer, x, y, r float64, h, m, s int) {
 	if (m > 59 || m < 0) || (s > 59 || s < 0) {
 		return
 	}
@@ -127,8 +129,14 @@ func drawtime(deck *generate.Deck, x, y, r float64, h, m, s int) {
 }
 
 func main() {
+	style := flag.String("style", "deck", "output style (deck, decksh, svg)")
+	flag.Parse()
 	now := time.Now()
-	deck := generate.NewSlides(os.Stdout, 0, 0)
+	deck, err := markup.New(os.Stdout, *style, 0, 0)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
 	deck.StartDeck()
 	deck.StartSlide()
 	deck.TextMid(50, 2, now.Format(time.Kitchen), "sans", 4, "")
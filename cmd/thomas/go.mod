@@ -1,3 +1,15 @@
 module github.com/ajstarks/utils/cmd/thomas
 
 go 1.22.0
+
+require (
+	github.com/ajstarks/utils/markup v0.0.0
+	github.com/ajstarks/utils/readpalette v0.0.0
+	github.com/ajstarks/utils/seeds v0.0.0
+)
+
+replace github.com/ajstarks/utils/readpalette => ../../readpalette
+
+replace github.com/ajstarks/utils/markup => ../../markup
+
+replace github.com/ajstarks/utils/seeds => ../../seeds
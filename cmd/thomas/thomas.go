@@ -2,8 +2,14 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"math/rand"
+	"os"
+
+	"github.com/ajstarks/utils/markup"
+	"github.com/ajstarks/utils/readpalette"
+	"github.com/ajstarks/utils/seeds"
 )
 
 type brushStack struct {
@@ -38,6 +44,9 @@ const (
 	defop     = 100.0
 )
 
+// deck is the markup writer
+var deck markup.Drawer
+
 var alldata = []brushStacks{
 	{
 		{n: 3, color: green2, opacity: defop},
@@ -221,6 +230,9 @@ var alldata = []brushStacks{
 	},
 }
 
+// rng is the random number source, seeded for each slide
+var rng = rand.New(rand.NewSource(1))
+
 // chip makes a four-sided polygon bounded to the rectangle
 // defined by (x, y) at its center with dimensions (w,h).
 // wd defines the depth inside the left and right sides,
@@ -236,20 +248,19 @@ func chip(x, y, w, h, wd, hd float64, color string, opacity float64) {
 	t := y + (h / 2)
 	b := y - (h / 2)
 
-	xp[0] = l + (w * rand.Float64())
-	yp[0] = t - (hd * rand.Float64())
+	xp[0] = l + (w * rng.Float64())
+	yp[0] = t - (hd * rng.Float64())
 
-	xp[1] = r - (wd * rand.Float64())
-	yp[1] = b + (h * rand.Float64())
+	xp[1] = r - (wd * rng.Float64())
+	yp[1] = b + (h * rng.Float64())
 
-	xp[2] = l + (w * rand.Float64())
-	yp[2] = b + (hd * rand.Float64())
+	xp[2] = l + (w * rng.Float64())
+	yp[2] = b + (hd * rng.Float64())
 
-	xp[3] = l + (wd * rand.Float64())
-	yp[3] = b + (h * rand.Float64())
+	xp[3] = l + (wd * rng.Float64())
+	yp[3] = b + (h * rng.Float64())
 
-	fmt.Printf("polygon \"%.2f %.2f %.2f %.2f\" \"%.2f %.2f %.2f %.2f\" \"%s\" %.2f\n",
-		xp[0], xp[1], xp[2], xp[3], yp[0], yp[1], yp[2], yp[3], color, opacity)
+	deck.Polygon(xp, yp, color, opacity)
 }
 
 // blob makes n number of ellipses bounded by the rectangle
@@ -261,9 +272,9 @@ func blob(x, y, w, h, wd, hd float64, n int, color string, opacity float64) {
 	b := y - (h / 2)
 	//fmt.Printf("rect %.2f %.2f %.2f %.2f \"%s\" %.2f\n", x, y, w, h, "black", 10.0)
 	for i := 0; i < n; i++ {
-		xp, yp := l+(w*rand.Float64()), b+(h*rand.Float64())
+		xp, yp := l+(w*rng.Float64()), b+(h*rng.Float64())
 		ew, eh := w*wd, h*hd
-		fmt.Printf("ellipse %.2f %.2f %.2f %.2f \"%s\" %.2f\n", xp, yp, ew, eh, color, opacity)
+		deck.Ellipse(xp, yp, ew, eh, color, opacity)
 	}
 }
 
@@ -303,23 +314,96 @@ func grid(x1, x2, y1, y2, w, h, wd, hd float64, nb int, palette []string) {
 	for x := x1; x <= x2; x += w {
 		for y := y1; y <= y2; y += h {
 			for n := 0; n < nb; n++ {
-				c := rand.Intn(len(palette))
+				c := rng.Intn(len(palette))
 				blob(x, y, w, h, wd, hd, 1, palette[c], 10)
 			}
 		}
 	}
 }
 
+// brushcolors lists the brush colors in the order
+// they are replaced by the colors of a user palette
+var brushcolors = []string{
+	blue1, green2, yellow1, red1, blue3, green1, violet1, yellow2, red2,
+	blue2, orange, violet2, blue4, pink, yellow3, violet3, green3, bluegreen,
+}
+
+// recolor replaces the brush colors with palette colors, reusing
+// palette colors when there are fewer of them than brush colors
+func recolor(data []brushStacks, palette []string) {
+	m := make(map[string]string, len(brushcolors))
+	for i, c := range brushcolors {
+		m[c] = palette[i%len(palette)]
+	}
+	for _, stacks := range data {
+		for i := range stacks {
+			if c, ok := m[stacks[i].color]; ok {
+				stacks[i].color = c
+			}
+		}
+	}
+}
+
+// userpalette recolors the data using a palette from the registry,
+// after adding the palettes from a file. If no palette is named and the file
+// holds a single palette, that one is used.
+func userpalette(data []brushStacks, pfile, name string) error {
+	registry := readpalette.Default()
+	if len(pfile) > 0 {
+		p, err := readpalette.LoadPalette(pfile)
+		if err != nil {
+			return err
+		}
+		registry.Merge(p, "user")
+		if len(name) == 0 && len(p) == 1 {
+			for k := range p {
+				name = k
+			}
+		}
+	}
+	if len(name) == 0 {
+		return nil
+	}
+	colors, ok := registry.Lookup(name)
+	if !ok || len(colors) == 0 {
+		return fmt.Errorf("no palette named %q", name)
+	}
+	recolor(data, colors)
+	return nil
+}
+
 func main() {
-	bgcolor := "white"
-	fmt.Println("deck")
-	fmt.Printf("slide \"%s\"\n", bgcolor)
+	var bgcolor, pfile, pname, style string
+	var seed int64
+	var variations int
+	flag.StringVar(&bgcolor, "bgcolor", "white", "background color")
+	flag.StringVar(&pfile, "p", "", "palette file")
+	flag.StringVar(&pname, "color", "", "palette name (default: the only palette in the palette file)")
+	flag.StringVar(&style, "style", "decksh", "output style (deck, decksh, svg)")
+	flag.Int64Var(&seed, "seed", 0, "random seed (0 for a new seed each run)")
+	flag.IntVar(&variations, "variations", 1, "number of variations, one per slide")
+	flag.Parse()
+	var err error
+	if deck, err = markup.New(os.Stdout, style, 0, 0); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	if err := userpalette(alldata, pfile, pname); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	deck.StartDeck()
 	w := 1.5
 	h := w * 1.6
 	wd := w * 0.05
 	hd := h * 0.05
-	alltower(alldata, 15, 10, w, h, wd, hd, 1)
-	fmt.Println("eslide")
+	for _, s := range seeds.Int64(seed, variations) {
+		rng = rand.New(rand.NewSource(s))
+		deck.StartSlide(bgcolor)
+		deck.Comment(fmt.Sprintf("thomas -seed %d", s))
+		alltower(alldata, 15, 10, w, h, wd, hd, 1)
+		deck.EndSlide()
+	}
 
 	// fmt.Printf("slide \"%s\"\n", bgcolor)
 	// blob(50, 50, 10, 15, 1, 1, 10, red1, 60)
@@ -329,6 +413,6 @@ func main() {
 	// grid(20, 80, 20, 80, w, h, 1, 1, 10, []string{"red", "green", "blue", "pink", "violet", "yellow", "orange"})
 	// fmt.Println("eslide")
 
-	fmt.Println("edeck")
+	deck.EndDeck()
 
 }
@@ -0,0 +1,408 @@
+//go:build ignore
+
+// gen -- make the commands in ../ into packages for the utils binary:
+// each command's main package is copied to internal/<command>, with main
+// renamed to Main, and commands.go, go.mod and go.sum are written to match.
+// Only go.mod and go.sum are committed; with -check, gen reports whether they are current.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	module    = "github.com/ajstarks/utils/cmd/utils"
+	generated = "// Code generated by gen.go; DO NOT EDIT.\n\n"
+)
+
+// out is the directory written to
+var out = "."
+
+// cmd is a command found in ../
+type cmd struct {
+	name        string
+	description string
+	flags       bool
+}
+
+// readme maps command names to their descriptions in the top README
+func readme(filename string) (map[string]string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	item := regexp.MustCompile(`^\* ([A-Za-z0-9]+) - (.*)`)
+	desc := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if m := item.FindStringSubmatch(scanner.Text()); m != nil {
+			desc[m[1]] = strings.TrimSpace(m[2])
+		}
+	}
+	return desc, scanner.Err()
+}
+
+// doc describes a command from its package comment, "name -- does X"
+func doc(name string, f *ast.File) string {
+	if f.Doc == nil {
+		return ""
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(f.Doc.Text()), "\n")
+	for _, sep := range []string{" -- ", ": ", " - "} {
+		if rest, ok := strings.CutPrefix(line, name+sep); ok {
+			return strings.TrimSuffix(rest, ".")
+		}
+	}
+	return ""
+}
+
+// pkgflags reports flags defined outside functions. utils gives each
+// command a new flag set when it runs, so those would be left undefined.
+func pkgflags(fset *token.FileSet, f *ast.File) error {
+	var err error
+	for _, d := range f.Decls {
+		if _, ok := d.(*ast.GenDecl); !ok {
+			continue
+		}
+		ast.Inspect(d, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || err != nil {
+				return err == nil
+			}
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+				if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == "flag" {
+					err = fmt.Errorf("%s: flag.%s outside a function; define flags in a function that main calls",
+						fset.Position(call.Pos()), sel.Sel.Name)
+				}
+			}
+			return err == nil
+		})
+	}
+	return err
+}
+
+// convert copies a command's main package to internal/<name>
+func convert(dir, name string) (cmd, error) {
+	c := cmd{name: name}
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return c, err
+	}
+	outdir := filepath.Join(out, "internal", name)
+	n := 0
+	for _, filename := range files {
+		if strings.HasSuffix(filename, "_test.go") {
+			continue
+		}
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+		if err != nil {
+			return c, err
+		}
+		if f.Name.Name != "main" {
+			continue
+		}
+		if err := pkgflags(fset, f); err != nil {
+			return c, err
+		}
+		f.Name.Name = name
+		for _, d := range f.Decls {
+			if fn, ok := d.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == "main" {
+				fn.Name.Name = "Main"
+			}
+		}
+		for _, imp := range f.Imports {
+			if imp.Path.Value == `"flag"` {
+				c.flags = true
+			}
+		}
+		if len(c.description) == 0 {
+			c.description = doc(name, f)
+		}
+		var buf bytes.Buffer
+		buf.WriteString(generated)
+		if err := format.Node(&buf, fset, f); err != nil {
+			return c, err
+		}
+		if err := os.MkdirAll(outdir, 0755); err != nil {
+			return c, err
+		}
+		if err := os.WriteFile(filepath.Join(outdir, filepath.Base(filename)), buf.Bytes(), 0644); err != nil {
+			return c, err
+		}
+		n++
+	}
+	if n == 0 {
+		return c, fmt.Errorf("%s: no main package", dir)
+	}
+	return c, nil
+}
+
+// writecommands writes the command table
+func writecommands(cmds []cmd) error {
+	var buf bytes.Buffer
+	buf.WriteString(generated)
+	buf.WriteString("package main\n\nimport (\n")
+	for _, c := range cmds {
+		fmt.Fprintf(&buf, "\t%q\n", module+"/internal/"+c.name)
+	}
+	buf.WriteString(")\n\nfunc init() {\n\tcommands = []command{\n")
+	for _, c := range cmds {
+		fmt.Fprintf(&buf, "\t\t{%q, %q, %v, %s.Main},\n", c.name, c.description, c.flags, c.name)
+	}
+	buf.WriteString("\t}\n}\n")
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(out, "commands.go"), src, 0644)
+}
+
+// version compares go versions like 1.21.6
+func version(a, b string) bool {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		x, _ := strconv.Atoi(as[i])
+		y, _ := strconv.Atoi(bs[i])
+		if x != y {
+			return x < y
+		}
+	}
+	return len(as) < len(bs)
+}
+
+// writemod merges the commands' go.mod and go.sum files
+func writemod(cmds []cmd) error {
+	gover := "1.21.6"
+	requires := map[string]string{} // path -> version
+	indirect := map[string]bool{}
+	replaces := map[string]string{}
+	sums := map[string]bool{}
+	for _, c := range cmds {
+		dir := filepath.Join("..", c.name)
+		data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		inblock := false
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			ind := strings.HasSuffix(line, "// indirect")
+			line = strings.TrimSpace(strings.TrimSuffix(line, "// indirect"))
+			switch {
+			case strings.HasPrefix(line, "go "):
+				if v := strings.TrimPrefix(line, "go "); version(gover, v) {
+					gover = v
+				}
+				continue
+			case line == "require (":
+				inblock = true
+				continue
+			case line == ")":
+				inblock = false
+				continue
+			case strings.HasPrefix(line, "replace "):
+				old, new, _ := strings.Cut(strings.TrimPrefix(line, "replace "), "=>")
+				replaces[strings.TrimSpace(old)] = strings.TrimSpace(new)
+				continue
+			case strings.HasPrefix(line, "require "):
+				line = strings.TrimPrefix(line, "require ")
+			case !inblock:
+				continue
+			}
+			f := strings.Fields(line)
+			if len(f) != 2 {
+				continue
+			}
+			// pseudo-versions of a module sort by date
+			if v, ok := requires[f[0]]; !ok || v < f[1] {
+				requires[f[0]] = f[1]
+			}
+			if _, seen := indirect[f[0]]; !seen || !ind {
+				indirect[f[0]] = ind
+			}
+		}
+		sum, err := os.ReadFile(filepath.Join(dir, "go.sum"))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		for _, line := range strings.Split(string(sum), "\n") {
+			if len(line) > 0 {
+				sums[line] = true
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "module %s\n\ngo %s\n", module, gover)
+	for _, ind := range []bool{false, true} {
+		var lines []string
+		for path, v := range requires {
+			if indirect[path] == ind {
+				line := "\t" + path + " " + v
+				if ind {
+					line += " // indirect"
+				}
+				lines = append(lines, line)
+			}
+		}
+		if len(lines) > 0 {
+			sort.Strings(lines)
+			fmt.Fprintf(&buf, "\nrequire (\n%s\n)\n", strings.Join(lines, "\n"))
+		}
+	}
+	var lines []string
+	for old, new := range replaces {
+		lines = append(lines, fmt.Sprintf("replace %s => %s", old, new))
+	}
+	sort.Strings(lines)
+	for _, line := range lines {
+		fmt.Fprintf(&buf, "\n%s\n", line)
+	}
+	if err := os.WriteFile(filepath.Join(out, "go.mod"), buf.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	lines = lines[:0]
+	for line := range sums {
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		os.Remove(filepath.Join(out, "go.sum"))
+		return nil
+	}
+	sort.Strings(lines)
+	return os.WriteFile(filepath.Join(out, "go.sum"), []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
+
+// genfiles lists the generated files that are committed, go.mod and go.sum,
+// in a directory; commands.go and internal/ are made when utils is built
+func genfiles(dir string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	for _, name := range []string{"go.mod", "go.sum"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err == nil {
+			files[name] = data
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// check compares the files in this directory with those generated in dir,
+// listing those that differ
+func check(dir string) (bool, error) {
+	want, err := genfiles(dir)
+	if err != nil {
+		return false, err
+	}
+	have, err := genfiles(".")
+	if err != nil {
+		return false, err
+	}
+	var stale []string
+	for name, data := range want {
+		if h, ok := have[name]; !ok {
+			stale = append(stale, name+" (missing)")
+		} else if !bytes.Equal(h, data) {
+			stale = append(stale, name)
+		}
+	}
+	for name := range have {
+		if _, ok := want[name]; !ok {
+			stale = append(stale, name+" (no longer made)")
+		}
+	}
+	sort.Strings(stale)
+	for _, name := range stale {
+		fmt.Fprintf(os.Stderr, "%s is out of date\n", name)
+	}
+	return len(stale) == 0, nil
+}
+
+func main() {
+	skip := flag.String("skip", "", "commands to leave out (comma separated)")
+	checkflag := flag.Bool("check", false, "report whether the generated files are current, without changing them")
+	flag.Parse()
+
+	if *checkflag {
+		dir, err := os.MkdirTemp("", "utilsgen")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		defer os.RemoveAll(dir)
+		out = dir
+	}
+
+	left := map[string]bool{"utils": true}
+	for _, s := range strings.Split(*skip, ",") {
+		left[strings.TrimSpace(s)] = true
+	}
+	desc, err := readme(filepath.Join("..", "..", "README.md"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	dirs, err := os.ReadDir("..")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	if err := os.RemoveAll(filepath.Join(out, "internal")); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	var cmds []cmd
+	for _, d := range dirs {
+		if !d.IsDir() || left[d.Name()] || !token.IsIdentifier(d.Name()) {
+			continue
+		}
+		c, err := convert(filepath.Join("..", d.Name()), d.Name())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		if s, ok := desc[c.name]; ok {
+			c.description = s
+		}
+		cmds = append(cmds, c)
+	}
+	if err := writecommands(cmds); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	if err := writemod(cmds); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	if *checkflag {
+		ok, err := check(out)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+		}
+		if !ok || err != nil {
+			fmt.Fprintln(os.Stderr, "run go generate in cmd/utils, and commit the result")
+			os.RemoveAll(out)
+			os.Exit(1)
+		}
+	}
+}
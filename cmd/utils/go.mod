@@ -0,0 +1,55 @@
+module github.com/ajstarks/utils/cmd/utils
+
+go 1.25.1
+
+require (
+	codeberg.org/go-pdf/fpdf v0.11.1
+	github.com/ajstarks/dchart2 v0.0.0-20200422132333-d422fc36b888
+	github.com/ajstarks/deck/generate v0.0.0-20220116200525-3f887d0c5850
+	github.com/ajstarks/gensvg v0.0.0-20210923152200-4042c242e95e
+	github.com/ajstarks/kml v0.0.0-20231216032752-dd72e94de437
+	github.com/ajstarks/utils/deckcheck v0.0.0
+	github.com/ajstarks/utils/imagescan v0.0.0
+	github.com/ajstarks/utils/locale v0.0.0
+	github.com/ajstarks/utils/markup v0.0.0
+	github.com/ajstarks/utils/readpalette v0.0.0
+	github.com/ajstarks/utils/roadmapcsv v0.0.0
+	github.com/ajstarks/utils/scale v0.0.0
+	github.com/ajstarks/utils/seeds v0.0.0
+	github.com/ajstarks/utils/settings v0.0.0
+	github.com/ajstarks/utils/source v0.0.0
+	github.com/ajstarks/utils/theme v0.0.0
+	github.com/flopp/go-findfont v0.1.0
+	github.com/mandolyte/mdtopdf v1.3.2
+	github.com/rwcarlsen/goexif v0.0.0-20190401172101-9e8deecbddbd
+	golang.org/x/net v0.40.0
+)
+
+require (
+	github.com/ajstarks/deck v0.0.0-20200831202436-30c9fc6549a9 // indirect
+	github.com/jung-kurt/gofpdf v1.16.2 // indirect
+	github.com/russross/blackfriday/v2 v2.1.0 // indirect
+	golang.org/x/image v0.18.0 // indirect
+)
+
+replace github.com/ajstarks/utils/deckcheck => ../../deckcheck
+
+replace github.com/ajstarks/utils/imagescan => ../../imagescan
+
+replace github.com/ajstarks/utils/locale => ../../locale
+
+replace github.com/ajstarks/utils/markup => ../../markup
+
+replace github.com/ajstarks/utils/readpalette => ../../readpalette
+
+replace github.com/ajstarks/utils/roadmapcsv => ../../roadmapcsv
+
+replace github.com/ajstarks/utils/scale => ../../scale
+
+replace github.com/ajstarks/utils/seeds => ../../seeds
+
+replace github.com/ajstarks/utils/settings => ../../settings
+
+replace github.com/ajstarks/utils/source => ../../source
+
+replace github.com/ajstarks/utils/theme => ../../theme
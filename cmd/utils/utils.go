@@ -0,0 +1,217 @@
+// utils -- run the commands in this repository from a single binary
+package main
+
+//go:generate go run gen.go
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// command is a subcommand
+type command struct {
+	name        string
+	description string
+	flags       bool // the command parses its options with the flag package
+	run         func()
+}
+
+// commands are set by commands.go, which go generate makes
+var commands []command
+
+// lookup finds a command by name
+func lookup(name string) (command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+// usage lists the commands
+func usage() {
+	w := 0
+	for _, c := range commands {
+		w = max(w, len(c.name))
+	}
+	fmt.Fprintln(os.Stderr, "Usage: utils command [options] [arguments]")
+	fmt.Fprintln(os.Stderr, "       utils help [command]")
+	fmt.Fprintln(os.Stderr, "       utils links directory")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands may also be run through a link named after the command.")
+	fmt.Fprintln(os.Stderr)
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "%-*s  %s\n", w, c.name, c.description)
+	}
+}
+
+// header starts a command's help
+func header(c command) {
+	if len(c.description) > 0 {
+		fmt.Fprintf(os.Stderr, "%s - %s\n\n", c.name, c.description)
+	}
+}
+
+// ishelp reports whether the arguments only ask for help
+func ishelp(args []string) bool {
+	if len(args) != 1 {
+		return false
+	}
+	switch args[0] {
+	case "-h", "-help", "--help":
+		return true
+	}
+	return false
+}
+
+// help shows a command's help: its description, usage line and options
+func help(c command) {
+	header(c)
+	if !c.flags {
+		fmt.Fprintf(os.Stderr, "Usage: %s [arguments]\n", c.name)
+		os.Exit(0)
+	}
+	text := options(c)
+	if !strings.Contains(text, "Usage:") {
+		fmt.Fprintf(os.Stderr, "Usage: %s [options] [arguments]\n\n", c.name)
+		text = strings.TrimLeft(text, "\n")
+	}
+	fmt.Fprint(os.Stderr, text)
+	os.Exit(0)
+}
+
+// usageshown stops a command once -help has shown its usage
+type usageshown struct{}
+
+// options runs a command with -help, returning what its usage writes.
+// The flag set continues on error, so the command's usage, not the
+// flag package, decides what is shown; the command is stopped there.
+func options(c command) string {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return ""
+	}
+	text := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		text <- string(b)
+	}()
+	stderr := os.Stderr
+	os.Stderr = w
+	func() {
+		defer func() {
+			if v := recover(); v != nil && v != (usageshown{}) {
+				panic(v)
+			}
+		}()
+		os.Args = []string{c.name, "-help"}
+		flag.CommandLine = flag.NewFlagSet(c.name, flag.ContinueOnError)
+		flag.Usage = flag.PrintDefaults
+		flag.CommandLine.Usage = func() {
+			flag.Usage()
+			panic(usageshown{})
+		}
+		c.run()
+	}()
+	os.Stderr = stderr
+	w.Close()
+	return <-text
+}
+
+// run runs a command as if it were invoked with args, giving it
+// a fresh flag set, and exits when the command returns
+func run(c command, args []string) {
+	os.Args = append([]string{c.name}, args...)
+	flag.CommandLine = flag.NewFlagSet(c.name, flag.ExitOnError)
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [options] [arguments]\n\n", c.name)
+		flag.PrintDefaults()
+	}
+	// like the standard command line, defer to flag.Usage,
+	// which the command may set
+	flag.CommandLine.Usage = func() { flag.Usage() }
+	c.run()
+	os.Exit(0)
+}
+
+// dispatch runs a command, answering requests for help consistently
+func dispatch(c command, args []string) {
+	if ishelp(args) {
+		help(c)
+	}
+	run(c, args)
+}
+
+// links makes a link to this binary for each command in dir
+func links(dir string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return err
+	}
+	for _, c := range commands {
+		link := filepath.Join(dir, c.name)
+		if _, err := os.Lstat(link); err == nil {
+			fmt.Fprintf(os.Stderr, "%s exists, skipping\n", link)
+			continue
+		}
+		if err := os.Symlink(exe, link); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func main() {
+	if len(commands) == 0 {
+		fmt.Fprintln(os.Stderr, "utils: no commands; run go generate in cmd/utils, then build again")
+		os.Exit(1)
+	}
+	// invoked through a link named after a command
+	name := strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe")
+	if c, ok := lookup(name); ok {
+		dispatch(c, os.Args[1:])
+	}
+
+	args := os.Args[1:]
+	if len(args) == 0 {
+		usage()
+		os.Exit(2)
+	}
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		if len(args) == 1 {
+			usage()
+			os.Exit(0)
+		}
+		c, ok := lookup(args[1])
+		if !ok {
+			fmt.Fprintf(os.Stderr, "utils: unknown command %q\n", args[1])
+			os.Exit(2)
+		}
+		help(c)
+	case "links":
+		if len(args) != 2 {
+			fmt.Fprintln(os.Stderr, "Usage: utils links directory")
+			os.Exit(2)
+		}
+		if err := links(args[1]); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	c, ok := lookup(args[0])
+	if !ok {
+		fmt.Fprintf(os.Stderr, "utils: unknown command %q (utils help lists the commands)\n", args[0])
+		os.Exit(2)
+	}
+	dispatch(c, args[1:])
+}
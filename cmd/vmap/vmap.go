@@ -1,13 +1,28 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
+	"math"
+	"os"
 )
 
+// mapping is a value mapped from one interval to another, as written with -json
+type mapping struct {
+	Value  float64 `json:"value"`
+	Low1   float64 `json:"low1"`
+	High1  float64 `json:"high1"`
+	Low2   float64 `json:"low2"`
+	High2  float64 `json:"high2"`
+	Result float64 `json:"result"`
+	Error  string  `json:"error,omitempty"`
+}
+
 func main() {
 	var value, low1, high1, low2, high2 float64
 	var format string
+	var jsonout bool
 
 	flag.Float64Var(&value, "value", 1, "value")
 	flag.Float64Var(&low1, "low1", 0, "low1")
@@ -15,8 +30,21 @@ func main() {
 	flag.Float64Var(&low2, "low2", 0, "low12")
 	flag.Float64Var(&high2, "high2", 100, "high2")
 	flag.StringVar(&format, "fmt", "%v", "format")
+	flag.BoolVar(&jsonout, "json", false, "write JSON")
 	flag.Parse()
 
+	if jsonout {
+		m := mapping{Value: value, Low1: low1, High1: high1, Low2: low2, High2: high2}
+		r := vmap(value, low1, high1, low2, high2)
+		if math.IsNaN(r) || math.IsInf(r, 0) {
+			m.Error = "low1 and high1 must differ"
+		} else {
+			m.Result = r
+		}
+		json.NewEncoder(os.Stdout).Encode(m)
+		return
+	}
+
 	fmt.Printf(fmt.Sprintf("%s (%s, %s) (%s, %s) = %s\n", format, format, format, format, format, format), value, low1, high1, low2, high2, vmap(value, low1, high1, low2, high2))
 
 }
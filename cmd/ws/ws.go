@@ -6,14 +6,14 @@ import (
 	"net/http"
 )
 
-var (
-	port    = flag.String("port", ":8080", "http service address")
-	logging = flag.Bool("log", false, "logging")
-	cert    = flag.String("cert", "", "cert path")
-	key     = flag.String("key", "", "key path")
-)
+// logging is set by the -log option
+var logging bool
 
 func main() {
+	port := flag.String("port", ":8080", "http service address")
+	cert := flag.String("cert", "", "cert path")
+	key := flag.String("key", "", "key path")
+	flag.BoolVar(&logging, "log", false, "logging")
 	flag.Parse()
 	http.Handle("/", http.HandlerFunc(sf))
 	log.Printf("ws: listen on %s", *port)
@@ -30,7 +30,7 @@ func main() {
 }
 
 func sf(c http.ResponseWriter, req *http.Request) {
-	if *logging {
+	if logging {
 		log.Printf("%v %v %v\n", req.RemoteAddr, req.URL.Path, req.UserAgent())
 	}
 	if len(req.URL.Path) > 2 {
@@ -0,0 +1,169 @@
+// Package deckcheck finds problems in deck markup, both deck XML and decksh:
+// malformed elements, unknown elements and attributes, coordinates off the
+// canvas, unknown colors and missing files.
+package deckcheck
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ajstarks/utils/readpalette"
+)
+
+// Severity classifies a problem
+type Severity int
+
+// Problem severities
+const (
+	Warning Severity = iota // probably a mistake, but renders
+	Error                   // will not render as intended, if at all
+)
+
+func (s Severity) String() string {
+	if s == Error {
+		return "error"
+	}
+	return "warning"
+}
+
+// Problem is a problem found in deck markup
+type Problem struct {
+	Line     int
+	Severity Severity
+	Element  string // element or command, if known
+	Message  string
+}
+
+func (p Problem) String() string {
+	if len(p.Element) > 0 {
+		return fmt.Sprintf("line %d: %s: %s: %s", p.Line, p.Severity, p.Element, p.Message)
+	}
+	return fmt.Sprintf("line %d: %s: %s", p.Line, p.Severity, p.Message)
+}
+
+// Format names a markup dialect
+type Format string
+
+// Supported formats
+const (
+	XML    Format = "xml"
+	Decksh Format = "decksh"
+)
+
+// DetectFormat decides whether markup is deck XML or decksh,
+// from its file extension, then from its first non-blank character
+func DetectFormat(filename string, head []byte) Format {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".xml":
+		return XML
+	case ".dsh", ".sh":
+		return Decksh
+	}
+	if h := bytes.TrimSpace(head); len(h) > 0 && h[0] == '<' {
+		return XML
+	}
+	return Decksh
+}
+
+// Check checks markup in the given format. File names in the markup
+// are resolved relative to dir. The error reports failure to read;
+// problems with the markup are returned sorted by line.
+func Check(r io.Reader, f Format, dir string) ([]Problem, error) {
+	c := &checker{dir: dir}
+	var err error
+	switch f {
+	case XML:
+		err = c.xml(r)
+	case Decksh:
+		err = c.decksh(r)
+	default:
+		return nil, fmt.Errorf("unknown format %q", f)
+	}
+	sort.SliceStable(c.problems, func(i, j int) bool { return c.problems[i].Line < c.problems[j].Line })
+	return c.problems, err
+}
+
+// CheckFile checks a file, detecting its format
+func CheckFile(filename string) ([]Problem, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Check(bytes.NewReader(data), DetectFormat(filename, data), filepath.Dir(filename))
+}
+
+// Errors counts the problems that are errors
+func Errors(problems []Problem) int {
+	n := 0
+	for _, p := range problems {
+		if p.Severity == Error {
+			n++
+		}
+	}
+	return n
+}
+
+// checker collects problems
+type checker struct {
+	dir      string
+	problems []Problem
+}
+
+func (c *checker) errorf(line int, elem, format string, args ...interface{}) {
+	c.problems = append(c.problems, Problem{Line: line, Severity: Error, Element: elem, Message: fmt.Sprintf(format, args...)})
+}
+
+func (c *checker) warnf(line int, elem, format string, args ...interface{}) {
+	c.problems = append(c.problems, Problem{Line: line, Severity: Warning, Element: elem, Message: fmt.Sprintf(format, args...)})
+}
+
+// number checks that a value is a number, returning it
+func (c *checker) number(line int, elem, name, value string) (float64, bool) {
+	v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		c.errorf(line, elem, "%s: %q is not a number", name, value)
+		return 0, false
+	}
+	return v, true
+}
+
+// coord checks that a percentage coordinate is on the canvas
+func (c *checker) coord(line int, elem, name string, v float64) {
+	if v < 0 || v > 100 {
+		c.warnf(line, elem, "%s: %v is off the canvas (0-100)", name, v)
+	}
+}
+
+// opacity checks an opacity percentage
+func (c *checker) opacity(line int, elem string, v float64) {
+	if v < 0 || v > 100 {
+		c.errorf(line, elem, "opacity: %v is out of range (0-100)", v)
+	}
+}
+
+// color checks a color
+func (c *checker) color(line int, elem, name, value string) {
+	if _, err := readpalette.ParseColor(value); err != nil {
+		c.errorf(line, elem, "%s: %q: %v", name, value, err)
+	}
+}
+
+// file checks that a file exists; URLs are not checked
+func (c *checker) file(line int, elem, name string) {
+	if len(name) == 0 || strings.Contains(name, "://") {
+		return
+	}
+	path := name
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(c.dir, path)
+	}
+	if _, err := os.Stat(path); err != nil {
+		c.errorf(line, elem, "file %q not found", name)
+	}
+}
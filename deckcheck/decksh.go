@@ -0,0 +1,300 @@
+package deckcheck
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// command describes a decksh command: its argument count,
+// and the kind of each leading argument; arguments past
+// the listed kinds are not checked
+type command struct {
+	min, max int // max < 0 for no limit
+	args     []int
+}
+
+// argument patterns shared by several commands
+var (
+	textcmd   = command{4, 8, []int{astr, acoord, acoord, anum, astr, acolor, aopac}}
+	listcmd   = command{3, 7, []int{acoord, acoord, anum, astr, acolor, aopac}}
+	shape4    = command{4, 6, []int{acoord, acoord, anum, anum, acolor, aopac}}
+	shape3    = command{3, 5, []int{acoord, acoord, anum, acolor, aopac}}
+	linecmd   = command{3, 6, []int{acoord, acoord, anum, anum, acolor, aopac}}
+	arrowcmd  = command{3, -1, []int{acoord, acoord}}
+	unchecked = command{0, -1, nil}
+)
+
+// commands lists the decksh commands
+var commands = map[string]command{
+	"deck":      {0, 0, nil},
+	"edeck":     {0, 0, nil},
+	"canvas":    {2, 2, []int{anum, anum}},
+	"slide":     {0, 2, []int{acolor, acolor}},
+	"eslide":    {0, 0, nil},
+	"text":      textcmd,
+	"ctext":     textcmd,
+	"etext":     textcmd,
+	"textblock": {5, 9, []int{astr, acoord, acoord, anum, anum, astr, acolor, aopac}},
+	"textfile":  {4, 8, []int{afile, acoord, acoord, anum, astr, acolor, aopac}},
+	"textcode":  {5, 6, []int{afile, acoord, acoord, anum, anum, acolor}},
+	"rtext":     {5, 9, []int{astr, acoord, acoord, anum, anum, astr, acolor, aopac}},
+	"arctext":   {7, 10, []int{astr, acoord, acoord, anum, anum, anum, anum, astr, acolor, aopac}},
+	"image":     {5, 7, []int{afile, acoord, acoord, anum, anum, anum}},
+	"cimage":    {6, 8, []int{afile, astr, acoord, acoord, anum, anum, anum}},
+	"list":      listcmd,
+	"blist":     listcmd,
+	"nlist":     listcmd,
+	"clist":     listcmd,
+	"li":        {1, 1, nil},
+	"elist":     {0, 0, nil},
+	"rect":      shape4,
+	"ellipse":   shape4,
+	"square":    shape3,
+	"circle":    shape3,
+	"rrect":     {5, 6, []int{acoord, acoord, anum, anum, anum, acolor}},
+	"pill":      {4, 5, []int{acoord, acoord, anum, anum, acolor}},
+	"line":      {4, 7, []int{acoord, acoord, acoord, acoord, anum, acolor, aopac}},
+	"hline":     linecmd,
+	"vline":     linecmd,
+	"arc":       {6, 9, []int{acoord, acoord, anum, anum, anum, anum, anum, acolor, aopac}},
+	"curve":     {6, 9, []int{acoord, acoord, acoord, acoord, acoord, acoord, anum, acolor, aopac}},
+	"polygon":   {2, 4, []int{alist, alist, acolor, aopac}},
+	"polyline":  {2, 5, []int{alist, alist, anum, acolor, aopac}},
+	"star":      {5, 7, []int{acoord, acoord, anum, anum, anum, acolor, aopac}},
+	"grid":      {5, -1, []int{afile, acoord, acoord, anum, anum, anum}},
+	"include":   {1, 1, []int{afile}},
+	"import":    {1, 1, []int{afile}},
+	"lcarrow":   arrowcmd,
+	"rcarrow":   arrowcmd,
+	"ucarrow":   arrowcmd,
+	"dcarrow":   arrowcmd,
+	"arrow":     arrowcmd,
+	"lbrace":    arrowcmd,
+	"rbrace":    arrowcmd,
+	"ubrace":    arrowcmd,
+	"dbrace":    arrowcmd,
+	"lbracket":  arrowcmd,
+	"rbracket":  arrowcmd,
+	"ubracket":  arrowcmd,
+	"dbracket":  arrowcmd,
+	"dchart":    unchecked,
+	"legend":    unchecked,
+	"for":       unchecked,
+	"efor":      {0, 0, nil},
+	"if":        unchecked,
+	"else":      {0, 0, nil},
+	"eif":       {0, 0, nil},
+	"def":       {1, -1, nil},
+	"edef":      {0, 0, nil},
+	"data":      {1, 1, nil},
+	"edata":     {0, 0, nil},
+}
+
+// blocks pairs the commands that open and close blocks
+var blocks = map[string]string{
+	"deck":  "edeck",
+	"slide": "eslide",
+	"for":   "efor",
+	"if":    "eif",
+	"def":   "edef",
+	"list":  "elist",
+	"blist": "elist",
+	"nlist": "elist",
+	"clist": "elist",
+}
+
+// assignment matches a variable assignment, like x=10 or x += 2
+var assignment = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*\s*[-+*/]?=`)
+
+// identifier matches a variable name
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// token is a decksh word, noting whether it was quoted
+type token struct {
+	s      string
+	quoted bool
+}
+
+// tokenize splits a line into words, keeping quoted strings together
+func tokenize(line string) ([]token, bool) {
+	var toks []token
+	for i := 0; i < len(line); {
+		switch {
+		case line[i] == ' ' || line[i] == '\t':
+			i++
+		case line[i] == '"':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(line) && line[j] != '"'; j++ {
+				if line[j] == '\\' && j+1 < len(line) {
+					j++
+				}
+				b.WriteByte(line[j])
+			}
+			if j >= len(line) {
+				return toks, false
+			}
+			toks = append(toks, token{b.String(), true})
+			i = j + 1
+		default:
+			j := i
+			for j < len(line) && line[j] != ' ' && line[j] != '\t' {
+				j++
+			}
+			toks = append(toks, token{line[i:j], false})
+			i = j
+		}
+	}
+	return toks, true
+}
+
+// open is an open decksh block
+type open struct {
+	cmd  string
+	line int
+}
+
+// decksh checks decksh markup
+func (c *checker) decksh(r io.Reader) error {
+	macros := map[string]bool{}
+	var stack []open
+	indata := false
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	n := 0
+	for scanner.Scan() {
+		n++
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "//") || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if indata {
+			indata = line != "edata"
+			continue
+		}
+		if assignment.MatchString(line) {
+			continue
+		}
+		toks, ok := tokenize(line)
+		if !ok {
+			c.errorf(n, "", "unterminated string")
+			continue
+		}
+		name := toks[0].s
+		args := toks[1:]
+		if macros[name] {
+			continue
+		}
+		cmd, ok := commands[name]
+		if !ok {
+			c.errorf(n, name, "unknown command")
+			continue
+		}
+		switch {
+		case name == "data":
+			indata = true
+		case name == "def" && len(args) > 0:
+			macros[args[0].s] = true
+		}
+		if end, ok := blocks[name]; ok {
+			stack = append(stack, open{end, n})
+		}
+		if closes(name) {
+			i := len(stack) - 1
+			for i >= 0 && stack[i].cmd != name {
+				i--
+			}
+			if i < 0 {
+				c.errorf(n, name, "without a matching start")
+			} else {
+				// blocks opened since were left open
+				for _, o := range stack[i+1:] {
+					c.errorf(o.line, "", "no %s for this block", o.cmd)
+				}
+				stack = stack[:i]
+			}
+		}
+		if len(args) < cmd.min || (cmd.max >= 0 && len(args) > cmd.max) {
+			c.errorf(n, name, "%d arguments, want %s", len(args), argrange(cmd))
+		}
+		c.args(n, name, cmd, args)
+	}
+	for _, o := range stack {
+		c.errorf(o.line, "", "no %s for this block", o.cmd)
+	}
+	return scanner.Err()
+}
+
+// closes reports whether a command closes a block
+func closes(name string) bool {
+	for _, end := range blocks {
+		if name == end {
+			return true
+		}
+	}
+	return false
+}
+
+// argrange describes the number of arguments a command takes
+func argrange(cmd command) string {
+	switch {
+	case cmd.min == cmd.max:
+		return strconv.Itoa(cmd.min)
+	case cmd.max < 0:
+		return "at least " + strconv.Itoa(cmd.min)
+	}
+	return strconv.Itoa(cmd.min) + "-" + strconv.Itoa(cmd.max)
+}
+
+// args checks a command's arguments; variables are accepted anywhere
+// a number or color is expected
+func (c *checker) args(line int, name string, cmd command, args []token) {
+	for i, a := range args {
+		if i >= len(cmd.args) {
+			return
+		}
+		variable := !a.quoted && identifier.MatchString(a.s)
+		what := "argument " + strconv.Itoa(i+1)
+		switch cmd.args[i] {
+		case anum:
+			if !variable {
+				c.number(line, name, what, a.s)
+			}
+		case acoord:
+			if !variable {
+				if f, ok := c.number(line, name, what, a.s); ok {
+					c.coord(line, name, what, f)
+				}
+			}
+		case aopac:
+			if !variable {
+				if f, ok := c.number(line, name, what, a.s); ok {
+					c.opacity(line, name, f)
+				}
+			}
+		case alist:
+			if !a.quoted {
+				continue // a variable holding the coordinates
+			}
+			for _, s := range strings.Fields(a.s) {
+				if identifier.MatchString(s) {
+					continue
+				}
+				if f, ok := c.number(line, name, what, s); ok {
+					c.coord(line, name, what, f)
+				}
+			}
+		case acolor:
+			if a.quoted {
+				c.color(line, name, what, a.s)
+			}
+		case afile:
+			if a.quoted {
+				c.file(line, name, a.s)
+			}
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package deckcheck
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+// attribute kinds
+const (
+	anum   = iota // any number
+	acoord        // percentage coordinate
+	alist         // space separated coordinates
+	acolor        // color
+	aopac         // opacity
+	afile         // file name
+	astr          // anything
+)
+
+// common attribute sets
+var (
+	textattrs = map[string]int{
+		"xp": acoord, "yp": acoord, "sp": anum, "lp": anum, "wp": anum,
+		"type": astr, "align": astr, "font": astr, "color": acolor,
+		"opacity": aopac, "link": astr, "rotation": anum, "file": afile,
+	}
+	shapeattrs = map[string]int{
+		"xp": acoord, "yp": acoord, "wp": anum, "hp": anum, "hr": anum,
+		"color": acolor, "opacity": aopac,
+		"gradcolor1": acolor, "gradcolor2": acolor, "gp": anum,
+	}
+)
+
+// elements lists the deck elements and their attributes
+var elements = map[string]map[string]int{
+	"deck":   {},
+	"canvas": {"width": anum, "height": anum},
+	"slide": {
+		"bg": acolor, "fg": acolor, "gradcolor1": acolor, "gradcolor2": acolor,
+		"gp": anum, "duration": astr,
+	},
+	"text": textattrs,
+	"list": textattrs,
+	"li":   {"color": acolor, "opacity": aopac, "font": astr, "sp": anum},
+	"image": {
+		"xp": acoord, "yp": acoord, "width": anum, "height": anum, "scale": anum,
+		"autoscale": astr, "name": afile, "caption": astr, "color": acolor,
+		"align": astr, "link": astr, "sp": anum,
+	},
+	"rect":    shapeattrs,
+	"ellipse": shapeattrs,
+	"line": {
+		"xp1": acoord, "yp1": acoord, "xp2": acoord, "yp2": acoord,
+		"sp": anum, "color": acolor, "opacity": aopac,
+	},
+	"arc": {
+		"xp": acoord, "yp": acoord, "wp": anum, "hp": anum, "a1": anum, "a2": anum,
+		"sp": anum, "color": acolor, "opacity": aopac,
+	},
+	"curve": {
+		"xp1": acoord, "yp1": acoord, "xp2": acoord, "yp2": acoord,
+		"xp3": acoord, "yp3": acoord, "sp": anum, "color": acolor, "opacity": aopac,
+	},
+	"polygon":     {"xc": alist, "yc": alist, "color": acolor, "opacity": aopac},
+	"polyline":    {"xc": alist, "yc": alist, "sp": anum, "color": acolor, "opacity": aopac},
+	"note":        {},
+	"metadata":    {},
+	"title":       {},
+	"creator":     {},
+	"subject":     {},
+	"publisher":   {},
+	"description": {},
+	"date":        {},
+}
+
+// textual elements may contain character data
+var textual = map[string]bool{
+	"text": true, "li": true, "note": true, "image": true,
+	"title": true, "creator": true, "subject": true, "publisher": true,
+	"description": true, "date": true,
+}
+
+// xml checks deck XML
+func (c *checker) xml(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	c.lexical(data)
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var stack []string
+	var text []bool // whether each open element has had text
+	for {
+		line, _ := dec.InputPos()
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var se *xml.SyntaxError
+			if errors.As(err, &se) {
+				c.errorf(se.Line, "", "%s", se.Msg)
+			} else {
+				c.errorf(line, "", "%v", err)
+			}
+			return nil
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			c.element(line, t)
+			stack = append(stack, t.Name.Local)
+			text = append(text, hasattr(t, "file"))
+		case xml.EndElement:
+			n := len(stack) - 1
+			if t.Name.Local == "text" && !text[n] {
+				c.warnf(line, "text", "no text")
+			}
+			stack, text = stack[:n], text[:n]
+		case xml.CharData:
+			s := strings.TrimSpace(string(t))
+			if len(s) == 0 {
+				continue
+			}
+			if len(stack) == 0 || !textual[stack[len(stack)-1]] {
+				where := "outside any element"
+				if len(stack) > 0 {
+					where = "in " + stack[len(stack)-1]
+				}
+				// report the line where the text itself starts
+				lead := strings.TrimLeft(string(t), " \t\r\n")
+				l := line + strings.Count(string(t)[:len(t)-len(lead)], "\n")
+				c.errorf(l, "", "stray text %q %s", s, where)
+				continue
+			}
+			text[len(text)-1] = true
+		}
+	}
+	if len(stack) > 0 {
+		line, _ := dec.InputPos()
+		c.errorf(line, stack[len(stack)-1], "not closed")
+	}
+	return nil
+}
+
+// hasattr reports whether an element has the named attribute
+func hasattr(t xml.StartElement, name string) bool {
+	for _, a := range t.Attr {
+		if a.Name.Local == name {
+			return true
+		}
+	}
+	return false
+}
+
+// element checks a start element's name and attributes
+func (c *checker) element(line int, t xml.StartElement) {
+	name := t.Name.Local
+	attrs, ok := elements[name]
+	if !ok {
+		c.errorf(line, name, "unknown element")
+		return
+	}
+	for _, a := range t.Attr {
+		an, v := a.Name.Local, a.Value
+		kind, ok := attrs[an]
+		if !ok {
+			c.errorf(line, name, "unknown attribute %q", an)
+			continue
+		}
+		switch kind {
+		case anum:
+			c.number(line, name, an, v)
+		case acoord:
+			if f, ok := c.number(line, name, an, v); ok {
+				c.coord(line, name, an, f)
+			}
+		case alist:
+			for _, s := range strings.Fields(v) {
+				if f, ok := c.number(line, name, an, s); ok {
+					c.coord(line, name, an, f)
+				}
+			}
+		case acolor:
+			c.color(line, name, an, v)
+		case aopac:
+			if f, ok := c.number(line, name, an, v); ok {
+				c.opacity(line, name, f)
+			}
+		case afile:
+			c.file(line, name, v)
+		}
+	}
+}
+
+// lexical finds attributes run together, like yp="1"sp="2",
+// which the XML decoder accepts
+func (c *checker) lexical(data []byte) {
+	line := 1
+	intag, inquote, closed := false, false, false
+	for i, b := range data {
+		if closed {
+			closed = false
+			if b != ' ' && b != '\t' && b != '\n' && b != '\r' && b != '/' && b != '>' {
+				c.errorf(line, "", "no space between attributes")
+			}
+		}
+		switch {
+		case b == '\n':
+			line++
+		case inquote:
+			if b == '"' {
+				inquote, closed = false, true
+			}
+		case !intag:
+			// comments, declarations and processing instructions are skipped
+			intag = b == '<' && i+1 < len(data) && data[i+1] != '!' && data[i+1] != '?'
+		case b == '"':
+			inquote = true
+		case b == '>':
+			intag = false
+		}
+	}
+}
@@ -0,0 +1,5 @@
+module github.com/ajstarks/utils/imagescan
+
+go 1.21.6
+
+require golang.org/x/image v0.18.0
@@ -0,0 +1,220 @@
+// Package imagescan reads many images at once: the files named on the
+// command line, and the images in directories, optionally walked
+// recursively and filtered by glob patterns, are read by a bounded pool
+// of workers, and the results are returned in a stable order.
+// GIF, JPEG, PNG, WebP, BMP and TIFF images are understood.
+package imagescan
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/tiff"
+	_ "golang.org/x/image/webp"
+)
+
+// Extensions are the patterns for images found in directories
+const Extensions = "*.gif,*.jpg,*.jpeg,*.png,*.webp,*.bmp,*.tif,*.tiff"
+
+// largerun is the number of files that makes a run worth reporting
+const largerun = 100
+
+// Scanner finds and reads images
+type Scanner struct {
+	Workers  int       // files read at once
+	Recurse  bool      // walk directories recursively
+	Match    string    // comma separated glob patterns for files in directories
+	Progress io.Writer // where progress on large runs is reported; nil for none
+}
+
+// Default reads eight files at once, reporting progress if the
+// standard error is a terminal
+var Default = Scanner{Workers: 8, Match: Extensions, Progress: terminal(os.Stderr)}
+
+// terminal returns f if it is a terminal, otherwise nil
+func terminal(f *os.File) io.Writer {
+	fi, err := f.Stat()
+	if err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		return nil
+	}
+	return f
+}
+
+// Flags defines the -r, -match and -workers options, which set the
+// default scanner when the command line is parsed
+func Flags() {
+	flag.BoolVar(&Default.Recurse, "r", Default.Recurse, "find images in subdirectories")
+	flag.StringVar(&Default.Match, "match", Default.Match, "comma separated patterns for images in directories")
+	flag.IntVar(&Default.Workers, "workers", Default.Workers, "number of files read at once")
+}
+
+// Image describes an image file
+type Image struct {
+	Name          string
+	Format        string // as registered with the image package: gif, jpeg, png, webp, bmp or tiff
+	Width, Height int
+	Err           error
+}
+
+// Config reads the format and size of an image file. Errors opening
+// the file are *fs.PathError; errors decoding it name the file.
+func Config(name string) Image {
+	im := Image{Name: name}
+	f, err := os.Open(name)
+	if err != nil {
+		im.Err = err
+		return im
+	}
+	defer f.Close()
+	c, format, err := image.DecodeConfig(f)
+	if err != nil {
+		im.Err = fmt.Errorf("%s: %w", name, err)
+		return im
+	}
+	im.Format, im.Width, im.Height = format, c.Width, c.Height
+	return im
+}
+
+// Decode reads an image in any of the formats understood, returning
+// the image and its format
+func Decode(r io.Reader) (image.Image, string, error) {
+	return image.Decode(r)
+}
+
+// Scan reads the configuration of the images named, with the default scanner
+func Scan(names []string, emit func(Image)) {
+	Default.Scan(names, emit)
+}
+
+// Scan reads the configuration of the images named, calling emit
+// with each, in the order of Files
+func (s Scanner) Scan(names []string, emit func(Image)) {
+	Map(s, s.Files(names), Config, emit)
+}
+
+// Files lists the files named, and the images in the directories named,
+// in lexical order. Names that cannot be read are kept, so that reading
+// them reports the error.
+func (s Scanner) Files(names []string) []string {
+	var files []string
+	for _, name := range names {
+		fi, err := os.Stat(name)
+		if err != nil || !fi.IsDir() {
+			files = append(files, name)
+			continue
+		}
+		filepath.WalkDir(name, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				files = append(files, path)
+				return nil
+			}
+			if path == name {
+				return nil
+			}
+			if d.IsDir() {
+				if !s.Recurse || strings.HasPrefix(d.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if s.match(d.Name()) {
+				files = append(files, path)
+			}
+			return nil
+		})
+	}
+	return files
+}
+
+// match reports whether a file name, ignoring case, matches one of the patterns
+func (s Scanner) match(name string) bool {
+	if strings.HasPrefix(name, ".") {
+		return false
+	}
+	name = strings.ToLower(name)
+	for _, pattern := range strings.Split(s.Match, ",") {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Map calls read for each name, with the scanner's workers,
+// and emit with the results, in the order of the names
+func Map[T any](s Scanner, names []string, read func(string) T, emit func(T)) {
+	workers := s.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	results := make([]T, len(names))
+	done := make([]chan struct{}, len(names))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = read(names[i])
+				close(done[i])
+			}
+		}()
+	}
+	go func() {
+		for i := range names {
+			jobs <- i
+		}
+		close(jobs)
+	}()
+
+	p := progress{w: s.Progress, total: len(names)}
+	for i := range names {
+		<-done[i]
+		emit(results[i])
+		p.report(i + 1)
+	}
+	wg.Wait()
+	p.end()
+}
+
+// progress reports how far a large run has gone, a few times a second
+type progress struct {
+	w     io.Writer
+	total int
+	last  time.Time
+}
+
+// report shows the number of files read so far
+func (p *progress) report(n int) {
+	if p.w == nil || p.total < largerun {
+		return
+	}
+	if now := time.Now(); n == p.total || now.Sub(p.last) >= 250*time.Millisecond {
+		fmt.Fprintf(p.w, "\r%d/%d files", n, p.total)
+		p.last = now
+	}
+}
+
+// end finishes the report
+func (p *progress) end() {
+	if p.w != nil && p.total >= largerun {
+		fmt.Fprintln(p.w)
+	}
+}
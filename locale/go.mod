@@ -0,0 +1,3 @@
+module github.com/ajstarks/utils/locale
+
+go 1.21.6
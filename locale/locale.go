@@ -0,0 +1,140 @@
+// Package locale formats numbers and dates for the audience of a chart:
+// digit grouping and decimal separators, SI suffixes, percentages, and
+// month and day names, for the common European and American locales.
+package locale
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Locale describes how numbers and dates are written
+type Locale struct {
+	Name     string
+	Group    string // digit group separator
+	Decimal  string // decimal separator
+	MinGroup int    // digits in the integer part before it is grouped
+	Percent  string // percent sign, including any space before it
+	Date     string // layout of a long date, as for time.Format
+	DayMonth string // layout of a day and month, as "Jan 2" or "2. Jan"
+
+	// month and day names, Sunday first; nil for the English names
+	Months, ShortMonths []string
+	Days, ShortDays     []string
+}
+
+const (
+	nbsp       = "\u00a0" // no-break space
+	narrownbsp = "\u202f" // narrow no-break space
+)
+
+var (
+	de = [4][]string{
+		{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+		{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+	}
+	fr = [4][]string{
+		{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+	}
+	es = [4][]string{
+		{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+	}
+	it = [4][]string{
+		{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		{"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+		{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+		{"dom", "lun", "mar", "mer", "gio", "ven", "sab"},
+	}
+	nl = [4][]string{
+		{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+		{"jan", "feb", "mrt", "apr", "mei", "jun", "jul", "aug", "sep", "okt", "nov", "dec"},
+		{"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+		{"zo", "ma", "di", "wo", "do", "vr", "za"},
+	}
+	pt = [4][]string{
+		{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		{"jan", "fev", "mar", "abr", "mai", "jun", "jul", "ago", "set", "out", "nov", "dez"},
+		{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+		{"dom", "seg", "ter", "qua", "qui", "sex", "sáb"},
+	}
+	sv = [4][]string{
+		{"januari", "februari", "mars", "april", "maj", "juni", "juli", "augusti", "september", "oktober", "november", "december"},
+		{"jan", "feb", "mars", "apr", "maj", "juni", "juli", "aug", "sep", "okt", "nov", "dec"},
+		{"söndag", "måndag", "tisdag", "onsdag", "torsdag", "fredag", "lördag"},
+		{"sön", "mån", "tis", "ons", "tors", "fre", "lör"},
+	}
+)
+
+// named makes a locale with the given names
+func named(l Locale, names [4][]string) Locale {
+	l.Months, l.ShortMonths, l.Days, l.ShortDays = names[0], names[1], names[2], names[3]
+	return l
+}
+
+// locales lists the known locales; the first of each language
+// is used when only the language is given
+var locales = []Locale{
+	{Name: "en-US", Group: ",", Decimal: ".", MinGroup: 4, Percent: "%", DayMonth: "Jan 2", Date: "January 2, 2006"},
+	{Name: "en-GB", Group: ",", Decimal: ".", MinGroup: 4, Percent: "%", DayMonth: "2 Jan", Date: "2 January 2006"},
+	named(Locale{Name: "de-DE", Group: ".", Decimal: ",", MinGroup: 4, Percent: nbsp + "%", DayMonth: "2. Jan", Date: "2. January 2006"}, de),
+	named(Locale{Name: "de-AT", Group: nbsp, Decimal: ",", MinGroup: 4, Percent: nbsp + "%", DayMonth: "2. Jan", Date: "2. January 2006"}, de),
+	named(Locale{Name: "de-CH", Group: "’", Decimal: ".", MinGroup: 4, Percent: "%", DayMonth: "2. Jan", Date: "2. January 2006"}, de),
+	named(Locale{Name: "fr-FR", Group: narrownbsp, Decimal: ",", MinGroup: 4, Percent: narrownbsp + "%", DayMonth: "2 Jan", Date: "2 January 2006"}, fr),
+	named(Locale{Name: "fr-BE", Group: narrownbsp, Decimal: ",", MinGroup: 4, Percent: nbsp + "%", DayMonth: "2 Jan", Date: "2 January 2006"}, fr),
+	named(Locale{Name: "fr-CH", Group: narrownbsp, Decimal: ",", MinGroup: 4, Percent: "%", DayMonth: "2 Jan", Date: "2 January 2006"}, fr),
+	named(Locale{Name: "es-ES", Group: ".", Decimal: ",", MinGroup: 5, Percent: nbsp + "%", DayMonth: "2 Jan", Date: "2 de January de 2006"}, es),
+	named(Locale{Name: "es-MX", Group: ",", Decimal: ".", MinGroup: 4, Percent: "%", DayMonth: "2 Jan", Date: "2 de January de 2006"}, es),
+	named(Locale{Name: "it-IT", Group: ".", Decimal: ",", MinGroup: 4, Percent: "%", DayMonth: "2 Jan", Date: "2 January 2006"}, it),
+	named(Locale{Name: "nl-NL", Group: ".", Decimal: ",", MinGroup: 4, Percent: "%", DayMonth: "2 Jan", Date: "2 January 2006"}, nl),
+	named(Locale{Name: "pt-PT", Group: nbsp, Decimal: ",", MinGroup: 5, Percent: "%", DayMonth: "2 Jan", Date: "2 de January de 2006"}, pt),
+	named(Locale{Name: "pt-BR", Group: ".", Decimal: ",", MinGroup: 4, Percent: "%", DayMonth: "2 Jan", Date: "2 de January de 2006"}, pt),
+	named(Locale{Name: "sv-SE", Group: nbsp, Decimal: ",", MinGroup: 4, Percent: nbsp + "%", DayMonth: "2 Jan", Date: "2 January 2006"}, sv),
+}
+
+// Default is the locale used when none is named
+var Default = locales[0]
+
+// Names lists the known locales
+func Names() []string {
+	names := make([]string, len(locales))
+	for i, l := range locales {
+		names[i] = l.Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup finds a locale by name, as "de-DE", "de_DE.UTF-8" or "de";
+// an empty name, "C" or "POSIX" is the default locale
+func Lookup(name string) (Locale, error) {
+	tag, _, _ := strings.Cut(name, ".") // encoding
+	tag, _, _ = strings.Cut(tag, "@")   // modifier
+	tag = strings.ReplaceAll(tag, "_", "-")
+	switch tag {
+	case "", "C", "POSIX":
+		return Default, nil
+	}
+	lang, _, _ := strings.Cut(tag, "-")
+	for _, l := range locales {
+		if strings.EqualFold(l.Name, tag) {
+			return l, nil
+		}
+	}
+	if lang == tag {
+		for _, l := range locales {
+			if strings.EqualFold(l.Name[:2], lang) {
+				return l, nil
+			}
+		}
+	}
+	return Default, fmt.Errorf("unknown locale %q (known locales: %s)", name, strings.Join(Names(), ", "))
+}
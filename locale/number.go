@@ -0,0 +1,142 @@
+package locale
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Formatter formats numbers in a style, for a locale
+type Formatter struct {
+	Locale Locale
+	Style  string // plain, group, si or percent
+	Digits int    // decimal places; -1 for as many as needed
+}
+
+// Styles lists the number styles:
+// plain (1234.5), group (1,234.5), si (1.2k) and percent (0.25 as 25%)
+var Styles = []string{"plain", "group", "si", "percent"}
+
+// si lists the SI prefixes for thousands
+var si = []string{"", "k", "M", "G", "T", "P", "E"}
+
+// NewFormatter makes a formatter for the named locale and a number
+// format, a style optionally followed by the decimal places, as "group:2"
+func NewFormatter(localename, numfmt string) (Formatter, error) {
+	loc, err := Lookup(localename)
+	if err != nil {
+		return Formatter{}, err
+	}
+	f := Formatter{Locale: loc, Digits: -1}
+	style, digits, ok := strings.Cut(numfmt, ":")
+	if ok {
+		if f.Digits, err = strconv.Atoi(digits); err != nil || f.Digits < 0 {
+			return f, fmt.Errorf("number format %q: bad decimal places %q", numfmt, digits)
+		}
+	}
+	for _, s := range Styles {
+		if style == s {
+			f.Style = s
+			return f, nil
+		}
+	}
+	return f, fmt.Errorf("unknown number format %q (use %s, optionally followed by :decimals)", numfmt, strings.Join(Styles, ", "))
+}
+
+// Format formats a number
+func (f Formatter) Format(v float64) string {
+	return f.format(v, f.Digits)
+}
+
+// FormatTick formats an axis tick; unless the formatter sets the
+// decimal places, there are as many as the tick spacing needs
+func (f Formatter) FormatTick(v, step float64) string {
+	digits := f.Digits
+	if digits < 0 {
+		switch f.Style {
+		case "si":
+			step /= unit(prefix(math.Max(math.Abs(v), step)))
+		case "percent":
+			step *= 100
+		}
+		digits = 0
+		if step > 0 {
+			digits = max(int(-math.Floor(math.Log10(step)+1e-9)), 0)
+		}
+	}
+	return f.format(v, digits)
+}
+
+// prefix returns the index of the SI prefix for a value
+func prefix(v float64) int {
+	if v < 1000 {
+		return 0
+	}
+	return min(int(math.Log10(v)/3), len(si)-1)
+}
+
+// unit returns the value of the ith SI prefix
+func unit(i int) float64 {
+	return math.Pow(1000, float64(i))
+}
+
+// format formats a number with the given decimal places
+func (f Formatter) format(v float64, digits int) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	switch f.Style {
+	case "si":
+		trim := digits < 0
+		if trim {
+			digits = 1
+		}
+		i := prefix(math.Abs(v))
+		// rounding may reach the next prefix: 999.96k is 1.0M
+		if whole, _, _ := strings.Cut(strconv.FormatFloat(math.Abs(v)/unit(i), 'f', digits, 64), "."); whole == "1000" && i < len(si)-1 {
+			i++
+		}
+		s := f.number(v/unit(i), digits, false)
+		if trim {
+			s = strings.TrimSuffix(s, f.Locale.Decimal+"0") // 1.0k reads better as 1k
+		}
+		return s + si[i]
+	case "percent":
+		return f.number(v*100, digits, false) + f.Locale.Percent
+	case "group":
+		return f.number(v, digits, true)
+	}
+	return f.number(v, digits, false)
+}
+
+// number writes a number with the locale's separators
+func (f Formatter) number(v float64, digits int, group bool) string {
+	if digits < 0 {
+		// drop floating point noise, as in 0.1+0.2
+		v, _ = strconv.ParseFloat(strconv.FormatFloat(v, 'g', 12, 64), 64)
+	}
+	s := strconv.FormatFloat(v, 'f', digits, 64)
+	sign := ""
+	if s[0] == '-' {
+		sign, s = "-", s[1:]
+		if z, _ := strconv.ParseFloat(s, 64); z == 0 {
+			sign = "" // no negative zero
+		}
+	}
+	whole, frac, _ := strings.Cut(s, ".")
+	if group && len(whole) >= f.Locale.MinGroup {
+		var b strings.Builder
+		for i, c := range whole {
+			if i > 0 && (len(whole)-i)%3 == 0 {
+				b.WriteString(f.Locale.Group)
+			}
+			b.WriteRune(c)
+		}
+		whole = b.String()
+	}
+	if len(frac) > 0 {
+		return sign + whole + f.Locale.Decimal + frac
+	}
+	return sign + whole
+}
@@ -0,0 +1,69 @@
+package locale
+
+import (
+	"strings"
+	"time"
+)
+
+// names are the layout elements for month and day names, longest first
+var names = []string{"January", "Jan", "Monday", "Mon"}
+
+// FormatTime formats a time like time.Format, with the locale's
+// month and day names
+func (l Locale) FormatTime(t time.Time, layout string) string {
+	layout = l.daymonth(layout)
+	if l.Months == nil {
+		return t.Format(layout)
+	}
+	var b strings.Builder
+	for len(layout) > 0 {
+		i, name := nextname(layout)
+		b.WriteString(t.Format(layout[:i]))
+		if len(name) == 0 {
+			break
+		}
+		switch name {
+		case "January":
+			b.WriteString(l.Months[t.Month()-1])
+		case "Jan":
+			b.WriteString(l.ShortMonths[t.Month()-1])
+		case "Monday":
+			b.WriteString(l.Days[t.Weekday()])
+		case "Mon":
+			b.WriteString(l.ShortDays[t.Weekday()])
+		}
+		layout = layout[i+len(name):]
+	}
+	return b.String()
+}
+
+// FormatDate formats a time as a long date
+func (l Locale) FormatDate(t time.Time) string {
+	return l.FormatTime(t, l.Date)
+}
+
+// daymonth puts the day and month in a layout in the locale's order
+func (l Locale) daymonth(layout string) string {
+	if len(l.DayMonth) == 0 {
+		return layout
+	}
+	for i := 0; i+5 <= len(layout); i++ {
+		// "Jan 2", but not "Jan 2006"
+		if layout[i:i+5] == "Jan 2" && (i+5 == len(layout) || layout[i+5] < '0' || layout[i+5] > '9') {
+			return layout[:i] + l.DayMonth + layout[i+5:]
+		}
+	}
+	return layout
+}
+
+// nextname finds the first month or day name in a layout
+func nextname(layout string) (int, string) {
+	for i := range layout {
+		for _, name := range names {
+			if strings.HasPrefix(layout[i:], name) {
+				return i, name
+			}
+		}
+	}
+	return len(layout), ""
+}
@@ -0,0 +1,36 @@
+package markup
+
+import "math"
+
+// Canvas is the size of a slide. Sizes of circles, arcs and text are
+// percentages of its width, and y coordinates percentages of its height,
+// so on any canvas that is not square, a vertical distance meant to match
+// a size must be corrected for the aspect ratio.
+type Canvas struct {
+	Width, Height float64
+}
+
+// Aspect returns the canvas aspect ratio, width over height
+func (c Canvas) Aspect() float64 {
+	return c.Width / c.Height
+}
+
+// Vertical converts a length measured like a size, as a percentage of
+// the width, to a percentage of the height
+func (c Canvas) Vertical(v float64) float64 {
+	return v * c.Aspect()
+}
+
+// Polar returns the Cartesian coordinates of the point at angle theta
+// (degrees) and distance r (a percentage of the width) from (cx, cy),
+// so that points placed around a center lie on a circle
+func (c Canvas) Polar(cx, cy, r, theta float64) (float64, float64) {
+	t := theta * (math.Pi / 180)
+	return cx + (r * math.Cos(t)), cy + (c.Vertical(r) * math.Sin(t))
+}
+
+// Relayout converts a vertical distance laid out for another canvas
+// to this one, keeping it in proportion to the sizes near it
+func (c Canvas) Relayout(v float64, from Canvas) float64 {
+	return v * c.Aspect() / from.Aspect()
+}
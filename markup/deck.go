@@ -0,0 +1,158 @@
+package markup
+
+import (
+	"fmt"
+	"io"
+)
+
+// Deck writes deck XML
+type Deck struct {
+	w             io.Writer
+	width, height int
+}
+
+// NewDeck makes a deck XML writer
+func NewDeck(w io.Writer, width, height int) *Deck {
+	return &Deck{w: w, width: width, height: height}
+}
+
+// attrs formats the optional font, color and opacity attributes
+func attrs(font, color string, opacity []float64) string {
+	s := ""
+	if len(font) > 0 {
+		s += fmt.Sprintf(" font=%q", font)
+	}
+	if len(color) > 0 {
+		s += fmt.Sprintf(" color=%q", color)
+	}
+	if o := op(opacity); o != 100 {
+		s += fmt.Sprintf(" opacity=\"%s\"", num(o))
+	}
+	return s
+}
+
+// StartDeck begins a deck, with a canvas if its size is known
+func (d *Deck) StartDeck() {
+	fmt.Fprintln(d.w, "<deck>")
+	if d.width > 0 && d.height > 0 {
+		fmt.Fprintf(d.w, "<canvas width=\"%d\" height=\"%d\"/>\n", d.width, d.height)
+	}
+}
+
+// EndDeck ends a deck
+func (d *Deck) EndDeck() { fmt.Fprintln(d.w, "</deck>") }
+
+// StartSlide begins a slide, with optional background and foreground colors
+func (d *Deck) StartSlide(colors ...string) {
+	switch len(colors) {
+	case 0:
+		fmt.Fprintln(d.w, "<slide>")
+	case 1:
+		fmt.Fprintf(d.w, "<slide bg=%q>\n", colors[0])
+	default:
+		fmt.Fprintf(d.w, "<slide bg=%q fg=%q>\n", colors[0], colors[1])
+	}
+}
+
+// EndSlide ends a slide
+func (d *Deck) EndSlide() { fmt.Fprintln(d.w, "</slide>") }
+
+// Comment makes a comment
+func (d *Deck) Comment(s string) { fmt.Fprintf(d.w, "<!-- %s -->\n", s) }
+
+func (d *Deck) text(align string, x, y float64, s, font string, size float64, color string, opacity []float64) {
+	a := ""
+	if len(align) > 0 {
+		a = fmt.Sprintf(" align=%q", align)
+	}
+	fmt.Fprintf(d.w, "<text xp=\"%s\" yp=\"%s\" sp=\"%s\"%s%s>%s</text>\n",
+		coord(x), coord(y), coord(size), a, attrs(font, color, opacity), xmlesc(s))
+}
+
+// Text places text, beginning at (x, y)
+func (d *Deck) Text(x, y float64, s, font string, size float64, color string, opacity ...float64) {
+	d.text("", x, y, s, font, size, color, opacity)
+}
+
+// TextMid places text centered at (x, y)
+func (d *Deck) TextMid(x, y float64, s, font string, size float64, color string, opacity ...float64) {
+	d.text("c", x, y, s, font, size, color, opacity)
+}
+
+// TextEnd places text ending at (x, y)
+func (d *Deck) TextEnd(x, y float64, s, font string, size float64, color string, opacity ...float64) {
+	d.text("e", x, y, s, font, size, color, opacity)
+}
+
+// TextBlock places text wrapped to width, beginning at (x, y)
+func (d *Deck) TextBlock(x, y float64, s, font string, size, width float64, color string, opacity ...float64) {
+	fmt.Fprintf(d.w, "<text xp=\"%s\" yp=\"%s\" sp=\"%s\" wp=\"%s\" type=\"block\"%s>%s</text>\n",
+		coord(x), coord(y), coord(size), coord(width), attrs(font, color, opacity), xmlesc(s))
+}
+
+// TextRotate places text rotated by angle degrees
+func (d *Deck) TextRotate(x, y float64, s, align, font string, angle, size float64, color string, opacity ...float64) {
+	a := ""
+	if len(align) > 0 {
+		a = fmt.Sprintf(" align=%q", align)
+	}
+	fmt.Fprintf(d.w, "<text xp=\"%s\" yp=\"%s\" sp=\"%s\" rotation=\"%s\"%s%s>%s</text>\n",
+		coord(x), coord(y), coord(size), num(angle), a, attrs(font, color, opacity), xmlesc(s))
+}
+
+// Circle makes a circle centered at (x, y) with diameter w
+func (d *Deck) Circle(x, y, w float64, color string, opacity ...float64) {
+	fmt.Fprintf(d.w, "<ellipse xp=\"%s\" yp=\"%s\" wp=\"%s\" hr=\"100\"%s/>\n", coord(x), coord(y), coord(w), attrs("", color, opacity))
+}
+
+// Ellipse makes an ellipse centered at (x, y)
+func (d *Deck) Ellipse(x, y, w, h float64, color string, opacity ...float64) {
+	fmt.Fprintf(d.w, "<ellipse xp=\"%s\" yp=\"%s\" wp=\"%s\" hp=\"%s\"%s/>\n", coord(x), coord(y), coord(w), coord(h), attrs("", color, opacity))
+}
+
+// Rect makes a rectangle centered at (x, y)
+func (d *Deck) Rect(x, y, w, h float64, color string, opacity ...float64) {
+	fmt.Fprintf(d.w, "<rect xp=\"%s\" yp=\"%s\" wp=\"%s\" hp=\"%s\"%s/>\n", coord(x), coord(y), coord(w), coord(h), attrs("", color, opacity))
+}
+
+// Square makes a square centered at (x, y) with sides w
+func (d *Deck) Square(x, y, w float64, color string, opacity ...float64) {
+	fmt.Fprintf(d.w, "<rect xp=\"%s\" yp=\"%s\" wp=\"%s\" hr=\"100\"%s/>\n", coord(x), coord(y), coord(w), attrs("", color, opacity))
+}
+
+// Line makes a line from (x1, y1) to (x2, y2)
+func (d *Deck) Line(x1, y1, x2, y2, size float64, color string, opacity ...float64) {
+	fmt.Fprintf(d.w, "<line xp1=\"%s\" yp1=\"%s\" xp2=\"%s\" yp2=\"%s\" sp=\"%s\"%s/>\n",
+		coord(x1), coord(y1), coord(x2), coord(y2), coord(size), attrs("", color, opacity))
+}
+
+// Polygon makes a filled polygon
+func (d *Deck) Polygon(x, y []float64, color string, opacity ...float64) {
+	fmt.Fprintf(d.w, "<polygon xc=\"%s\" yc=\"%s\"%s/>\n", coords(x), coords(y), attrs("", color, opacity))
+}
+
+// Polyline makes connected line segments
+func (d *Deck) Polyline(x, y []float64, size float64, color string, opacity ...float64) {
+	fmt.Fprintf(d.w, "<polyline xc=\"%s\" yc=\"%s\" sp=\"%s\"%s/>\n", coords(x), coords(y), coord(size), attrs("", color, opacity))
+}
+
+// Arc makes an arc centered at (x, y) from angle a1 to a2 (degrees)
+func (d *Deck) Arc(x, y, w, h, size, a1, a2 float64, color string, opacity ...float64) {
+	fmt.Fprintf(d.w, "<arc xp=\"%s\" yp=\"%s\" wp=\"%s\" hp=\"%s\" a1=\"%s\" a2=\"%s\" sp=\"%s\"%s/>\n",
+		coord(x), coord(y), coord(w), coord(h), coord(a1), coord(a2), coord(size), attrs("", color, opacity))
+}
+
+// Curve makes a quadratic Bezier curve from (x1, y1) to (x3, y3), with control point (x2, y2)
+func (d *Deck) Curve(x1, y1, x2, y2, x3, y3, size float64, color string, opacity ...float64) {
+	fmt.Fprintf(d.w, "<curve xp1=\"%s\" yp1=\"%s\" xp2=\"%s\" yp2=\"%s\" xp3=\"%s\" yp3=\"%s\" sp=\"%s\"%s/>\n",
+		coord(x1), coord(y1), coord(x2), coord(y2), coord(x3), coord(y3), coord(size), attrs("", color, opacity))
+}
+
+// Image places an image (w and h in pixels) centered at (x, y), scaled by a percentage
+func (d *Deck) Image(x, y float64, w, h int, scale float64, name string) {
+	sc := ""
+	if scale > 0 && scale != 100 {
+		sc = fmt.Sprintf(" scale=\"%s\"", num(scale))
+	}
+	fmt.Fprintf(d.w, "<image xp=\"%s\" yp=\"%s\" width=\"%d\" height=\"%d\"%s name=%q/>\n", coord(x), coord(y), w, h, sc, name)
+}
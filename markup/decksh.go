@@ -0,0 +1,145 @@
+package markup
+
+import (
+	"fmt"
+	"io"
+)
+
+// Decksh writes decksh markup
+type Decksh struct {
+	w             io.Writer
+	width, height int
+}
+
+// NewDecksh makes a decksh writer
+func NewDecksh(w io.Writer, width, height int) *Decksh {
+	return &Decksh{w: w, width: width, height: height}
+}
+
+// shcolor formats the optional color and opacity arguments
+func shcolor(color string, opacity []float64) string {
+	if len(color) == 0 {
+		color = "black"
+	}
+	s := fmt.Sprintf(" %q", color)
+	if o := op(opacity); o != 100 {
+		s += " " + num(o)
+	}
+	return s
+}
+
+// shfont formats the font argument
+func shfont(font string) string {
+	if len(font) == 0 {
+		font = "sans"
+	}
+	return fmt.Sprintf(" %q", font)
+}
+
+// StartDeck begins a deck, with a canvas if its size is known
+func (d *Decksh) StartDeck() {
+	fmt.Fprintln(d.w, "deck")
+	if d.width > 0 && d.height > 0 {
+		fmt.Fprintf(d.w, "canvas %d %d\n", d.width, d.height)
+	}
+}
+
+// EndDeck ends a deck
+func (d *Decksh) EndDeck() { fmt.Fprintln(d.w, "edeck") }
+
+// StartSlide begins a slide, with optional background and foreground colors
+func (d *Decksh) StartSlide(colors ...string) {
+	fmt.Fprint(d.w, "slide")
+	for _, c := range colors[:min(len(colors), 2)] {
+		fmt.Fprintf(d.w, " %q", c)
+	}
+	fmt.Fprintln(d.w)
+}
+
+// EndSlide ends a slide
+func (d *Decksh) EndSlide() { fmt.Fprintln(d.w, "eslide") }
+
+// Comment makes a comment
+func (d *Decksh) Comment(s string) { fmt.Fprintf(d.w, "// %s\n", s) }
+
+func (d *Decksh) text(cmd string, x, y float64, s, font string, size float64, color string, opacity []float64) {
+	fmt.Fprintf(d.w, "%s %q %s %s %s%s%s\n", cmd, s, coord(x), coord(y), coord(size), shfont(font), shcolor(color, opacity))
+}
+
+// Text places text, beginning at (x, y)
+func (d *Decksh) Text(x, y float64, s, font string, size float64, color string, opacity ...float64) {
+	d.text("text", x, y, s, font, size, color, opacity)
+}
+
+// TextMid places text centered at (x, y)
+func (d *Decksh) TextMid(x, y float64, s, font string, size float64, color string, opacity ...float64) {
+	d.text("ctext", x, y, s, font, size, color, opacity)
+}
+
+// TextEnd places text ending at (x, y)
+func (d *Decksh) TextEnd(x, y float64, s, font string, size float64, color string, opacity ...float64) {
+	d.text("etext", x, y, s, font, size, color, opacity)
+}
+
+// TextBlock places text wrapped to width, beginning at (x, y)
+func (d *Decksh) TextBlock(x, y float64, s, font string, size, width float64, color string, opacity ...float64) {
+	fmt.Fprintf(d.w, "textblock %q %s %s %s %s%s%s\n", s, coord(x), coord(y), coord(width), coord(size), shfont(font), shcolor(color, opacity))
+}
+
+// TextRotate places text rotated by angle degrees
+func (d *Decksh) TextRotate(x, y float64, s, align, font string, angle, size float64, color string, opacity ...float64) {
+	fmt.Fprintf(d.w, "rtext %q %s %s %s %s%s%s\n", s, coord(x), coord(y), num(angle), coord(size), shfont(font), shcolor(color, opacity))
+}
+
+// Circle makes a circle centered at (x, y) with diameter w
+func (d *Decksh) Circle(x, y, w float64, color string, opacity ...float64) {
+	fmt.Fprintf(d.w, "circle %s %s %s%s\n", coord(x), coord(y), coord(w), shcolor(color, opacity))
+}
+
+// Ellipse makes an ellipse centered at (x, y)
+func (d *Decksh) Ellipse(x, y, w, h float64, color string, opacity ...float64) {
+	fmt.Fprintf(d.w, "ellipse %s %s %s %s%s\n", coord(x), coord(y), coord(w), coord(h), shcolor(color, opacity))
+}
+
+// Rect makes a rectangle centered at (x, y)
+func (d *Decksh) Rect(x, y, w, h float64, color string, opacity ...float64) {
+	fmt.Fprintf(d.w, "rect %s %s %s %s%s\n", coord(x), coord(y), coord(w), coord(h), shcolor(color, opacity))
+}
+
+// Square makes a square centered at (x, y) with sides w
+func (d *Decksh) Square(x, y, w float64, color string, opacity ...float64) {
+	fmt.Fprintf(d.w, "square %s %s %s%s\n", coord(x), coord(y), coord(w), shcolor(color, opacity))
+}
+
+// Line makes a line from (x1, y1) to (x2, y2)
+func (d *Decksh) Line(x1, y1, x2, y2, size float64, color string, opacity ...float64) {
+	fmt.Fprintf(d.w, "line %s %s %s %s %s%s\n", coord(x1), coord(y1), coord(x2), coord(y2), coord(size), shcolor(color, opacity))
+}
+
+// Polygon makes a filled polygon
+func (d *Decksh) Polygon(x, y []float64, color string, opacity ...float64) {
+	fmt.Fprintf(d.w, "polygon %q %q%s\n", coords(x), coords(y), shcolor(color, opacity))
+}
+
+// Polyline makes connected line segments
+func (d *Decksh) Polyline(x, y []float64, size float64, color string, opacity ...float64) {
+	fmt.Fprintf(d.w, "polyline %q %q %s%s\n", coords(x), coords(y), coord(size), shcolor(color, opacity))
+}
+
+// Arc makes an arc centered at (x, y) from angle a1 to a2 (degrees)
+func (d *Decksh) Arc(x, y, w, h, size, a1, a2 float64, color string, opacity ...float64) {
+	fmt.Fprintf(d.w, "arc %s %s %s %s %s %s %s%s\n", coord(x), coord(y), coord(w), coord(h), coord(a1), coord(a2), coord(size), shcolor(color, opacity))
+}
+
+// Curve makes a quadratic Bezier curve from (x1, y1) to (x3, y3), with control point (x2, y2)
+func (d *Decksh) Curve(x1, y1, x2, y2, x3, y3, size float64, color string, opacity ...float64) {
+	fmt.Fprintf(d.w, "curve %s %s %s %s %s %s %s%s\n", coord(x1), coord(y1), coord(x2), coord(y2), coord(x3), coord(y3), coord(size), shcolor(color, opacity))
+}
+
+// Image places an image (w and h in pixels) centered at (x, y), scaled by a percentage
+func (d *Decksh) Image(x, y float64, w, h int, scale float64, name string) {
+	if scale <= 0 {
+		scale = 100
+	}
+	fmt.Fprintf(d.w, "image %q %s %s %d %d %s\n", name, coord(x), coord(y), w, h, num(scale))
+}
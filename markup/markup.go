@@ -0,0 +1,100 @@
+// Package markup draws deck slides in one of several dialects:
+// deck XML, decksh, or SVG. Coordinates and sizes are percentages of
+// the canvas, as in deck markup; the methods follow deck/generate.
+package markup
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Drawer is implemented by each markup dialect.
+// Optional opacity arguments are percentages (default 100).
+type Drawer interface {
+	StartDeck()
+	EndDeck()
+	StartSlide(colors ...string) // background, foreground
+	EndSlide()
+	Comment(s string)
+
+	Text(x, y float64, s, font string, size float64, color string, opacity ...float64)
+	TextMid(x, y float64, s, font string, size float64, color string, opacity ...float64) // centered text (ctext)
+	TextEnd(x, y float64, s, font string, size float64, color string, opacity ...float64) // end aligned text (etext)
+	TextBlock(x, y float64, s, font string, size, width float64, color string, opacity ...float64)
+	TextRotate(x, y float64, s, align, font string, angle, size float64, color string, opacity ...float64)
+
+	Circle(x, y, w float64, color string, opacity ...float64)
+	Ellipse(x, y, w, h float64, color string, opacity ...float64)
+	Rect(x, y, w, h float64, color string, opacity ...float64)
+	Square(x, y, w float64, color string, opacity ...float64)
+	Line(x1, y1, x2, y2, size float64, color string, opacity ...float64)
+	Polygon(x, y []float64, color string, opacity ...float64)
+	Polyline(x, y []float64, size float64, color string, opacity ...float64)
+	Arc(x, y, w, h, size, a1, a2 float64, color string, opacity ...float64)
+	Curve(x1, y1, x2, y2, x3, y3, size float64, color string, opacity ...float64)
+	Image(x, y float64, w, h int, scale float64, name string)
+}
+
+// Styles lists the supported dialects
+var Styles = []string{"deck", "decksh", "svg"}
+
+// default canvas size, used by SVG when none is specified
+const (
+	DefaultWidth  = 792
+	DefaultHeight = 612
+)
+
+// New makes a Drawer for the named style writing to w.
+// If width and height are non-zero, they set the canvas size.
+func New(w io.Writer, style string, width, height int) (Drawer, error) {
+	switch style {
+	case "deck", "xml":
+		return NewDeck(w, width, height), nil
+	case "decksh":
+		return NewDecksh(w, width, height), nil
+	case "svg":
+		return NewSVG(w, width, height), nil
+	}
+	return nil, fmt.Errorf("unknown style %q (use %s)", style, strings.Join(Styles, ", "))
+}
+
+// op returns the opacity from optional arguments
+func op(opacity []float64) float64 {
+	if len(opacity) > 0 {
+		return opacity[0]
+	}
+	return 100
+}
+
+// num formats a number in its shortest form
+func num(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// coord formats a coordinate or size
+func coord(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+// coords formats a list of coordinates
+func coords(v []float64) string {
+	s := make([]string, len(v))
+	for i, c := range v {
+		s[i] = coord(c)
+	}
+	return strings.Join(s, " ")
+}
+
+// xmlmap defines the XML substitutions
+var xmlmap = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	`"`, "&quot;")
+
+// xmlesc XML escapes a string
+func xmlesc(s string) string {
+	return xmlmap.Replace(s)
+}
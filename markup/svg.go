@@ -0,0 +1,284 @@
+package markup
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/ajstarks/utils/readpalette"
+)
+
+// SVG writes SVG. Slides are stacked vertically in a single
+// document, which is written when the deck ends.
+type SVG struct {
+	w             io.Writer
+	width, height float64
+	slides        []*bytes.Buffer
+	fg            string
+}
+
+// NewSVG makes an SVG writer; zero dimensions select the default canvas
+func NewSVG(w io.Writer, width, height int) *SVG {
+	if width <= 0 || height <= 0 {
+		width, height = DefaultWidth, DefaultHeight
+	}
+	return &SVG{w: w, width: float64(width), height: float64(height)}
+}
+
+// fonts maps deck font names to SVG font families
+var fonts = map[string]string{
+	"sans":  "sans-serif",
+	"serif": "serif",
+	"mono":  "monospace",
+}
+
+// cur returns the current slide
+func (s *SVG) cur() *bytes.Buffer {
+	if len(s.slides) == 0 {
+		s.StartSlide()
+	}
+	return s.slides[len(s.slides)-1]
+}
+
+// px, py, pw, ph map percentages to canvas units
+func (s *SVG) px(v float64) float64 { return v / 100 * s.width }
+func (s *SVG) py(v float64) float64 { return s.height - v/100*s.height }
+func (s *SVG) pw(v float64) float64 { return v / 100 * s.width }
+func (s *SVG) ph(v float64) float64 { return v / 100 * s.height }
+
+// paint returns the SVG color and opacity for a deck color
+func paint(color string, opacity []float64) (string, float64) {
+	o := op(opacity) / 100
+	c, err := readpalette.ParseColor(color)
+	if err != nil {
+		return color, o
+	}
+	return fmt.Sprintf("rgb(%d,%d,%d)", c.R, c.G, c.B), o * float64(c.A) / 255
+}
+
+// fill formats fill attributes
+func fill(color string, opacity []float64) string {
+	c, o := paint(color, opacity)
+	if o < 1 {
+		return fmt.Sprintf("fill=%q fill-opacity=\"%s\"", c, num(math.Round(o*1000)/1000))
+	}
+	return fmt.Sprintf("fill=%q", c)
+}
+
+// stroke formats stroke attributes
+func stroke(color string, width float64, opacity []float64) string {
+	c, o := paint(color, opacity)
+	s := fmt.Sprintf("fill=\"none\" stroke=%q stroke-width=%q", c, coord(width))
+	if o < 1 {
+		s += fmt.Sprintf(" stroke-opacity=\"%s\"", num(math.Round(o*1000)/1000))
+	}
+	return s
+}
+
+// StartDeck begins a deck
+func (s *SVG) StartDeck() {}
+
+// EndDeck writes the slides as one SVG document
+func (s *SVG) EndDeck() {
+	n := max(len(s.slides), 1)
+	fmt.Fprintf(s.w, "<?xml version=\"1.0\"?>\n<svg xmlns=\"http://www.w3.org/2000/svg\" xmlns:xlink=\"http://www.w3.org/1999/xlink\" width=\"%s\" height=\"%s\" viewBox=\"0 0 %s %s\">\n",
+		num(s.width), num(s.height*float64(n)), num(s.width), num(s.height*float64(n)))
+	for i, b := range s.slides {
+		fmt.Fprintf(s.w, "<g transform=\"translate(0,%s)\">\n", num(s.height*float64(i)))
+		s.w.Write(b.Bytes())
+		fmt.Fprintln(s.w, "</g>")
+	}
+	fmt.Fprintln(s.w, "</svg>")
+}
+
+// StartSlide begins a slide, with optional background and foreground colors
+func (s *SVG) StartSlide(colors ...string) {
+	b := new(bytes.Buffer)
+	s.slides = append(s.slides, b)
+	s.fg = "black"
+	if len(colors) > 0 && len(colors[0]) > 0 {
+		fmt.Fprintf(b, "<rect x=\"0\" y=\"0\" width=\"%s\" height=\"%s\" %s/>\n", num(s.width), num(s.height), fill(colors[0], nil))
+	}
+	if len(colors) > 1 && len(colors[1]) > 0 {
+		s.fg = colors[1]
+	}
+}
+
+// EndSlide ends a slide
+func (s *SVG) EndSlide() {}
+
+// Comment makes a comment
+func (s *SVG) Comment(c string) {
+	fmt.Fprintf(s.cur(), "<!-- %s -->\n", strings.ReplaceAll(c, "--", "- -"))
+}
+
+// textattr formats the common text attributes
+func (s *SVG) textattr(font string, size float64, color string, opacity []float64) string {
+	if len(color) == 0 {
+		color = s.fg
+	}
+	family, ok := fonts[font]
+	if !ok {
+		family = "sans-serif"
+		if len(font) > 0 {
+			family = font
+		}
+	}
+	return fmt.Sprintf("font-family=%q font-size=%q %s", family, coord(s.pw(size)), fill(color, opacity))
+}
+
+func (s *SVG) text(anchor string, x, y float64, t, font string, size float64, color string, opacity []float64) {
+	a := ""
+	if len(anchor) > 0 {
+		a = fmt.Sprintf(" text-anchor=%q", anchor)
+	}
+	fmt.Fprintf(s.cur(), "<text x=%q y=%q%s %s>%s</text>\n", coord(s.px(x)), coord(s.py(y)), a, s.textattr(font, size, color, opacity), xmlesc(t))
+}
+
+// Text places text, beginning at (x, y)
+func (s *SVG) Text(x, y float64, t, font string, size float64, color string, opacity ...float64) {
+	s.text("", x, y, t, font, size, color, opacity)
+}
+
+// TextMid places text centered at (x, y)
+func (s *SVG) TextMid(x, y float64, t, font string, size float64, color string, opacity ...float64) {
+	s.text("middle", x, y, t, font, size, color, opacity)
+}
+
+// TextEnd places text ending at (x, y)
+func (s *SVG) TextEnd(x, y float64, t, font string, size float64, color string, opacity ...float64) {
+	s.text("end", x, y, t, font, size, color, opacity)
+}
+
+// TextBlock places text wrapped to width, beginning at (x, y).
+// Lines are broken assuming an average character width of half the font size.
+func (s *SVG) TextBlock(x, y float64, t, font string, size, width float64, color string, opacity ...float64) {
+	perline := int(width / (size * 0.5))
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(t) {
+		if len(line) > 0 && len(line)+1+len(word) > perline {
+			lines = append(lines, line)
+			line = ""
+		}
+		if len(line) > 0 {
+			line += " "
+		}
+		line += word
+	}
+	if len(line) > 0 {
+		lines = append(lines, line)
+	}
+	b := s.cur()
+	fmt.Fprintf(b, "<text %s>\n", s.textattr(font, size, color, opacity))
+	for i, l := range lines {
+		fmt.Fprintf(b, "<tspan x=%q y=%q>%s</tspan>\n", coord(s.px(x)), coord(s.py(y)+float64(i)*s.pw(size)*1.8), xmlesc(l))
+	}
+	fmt.Fprintln(b, "</text>")
+}
+
+// TextRotate places text rotated by angle degrees
+func (s *SVG) TextRotate(x, y float64, t, align, font string, angle, size float64, color string, opacity ...float64) {
+	anchor := ""
+	switch align {
+	case "c", "center", "middle", "mid":
+		anchor = " text-anchor=\"middle\""
+	case "e", "end":
+		anchor = " text-anchor=\"end\""
+	}
+	px, py := coord(s.px(x)), coord(s.py(y))
+	fmt.Fprintf(s.cur(), "<text x=%q y=%q%s transform=\"rotate(%s,%s,%s)\" %s>%s</text>\n",
+		px, py, anchor, num(-angle), px, py, s.textattr(font, size, color, opacity), xmlesc(t))
+}
+
+// Circle makes a circle centered at (x, y) with diameter w
+func (s *SVG) Circle(x, y, w float64, color string, opacity ...float64) {
+	fmt.Fprintf(s.cur(), "<circle cx=%q cy=%q r=%q %s/>\n", coord(s.px(x)), coord(s.py(y)), coord(s.pw(w)/2), fill(color, opacity))
+}
+
+// Ellipse makes an ellipse centered at (x, y)
+func (s *SVG) Ellipse(x, y, w, h float64, color string, opacity ...float64) {
+	fmt.Fprintf(s.cur(), "<ellipse cx=%q cy=%q rx=%q ry=%q %s/>\n", coord(s.px(x)), coord(s.py(y)), coord(s.pw(w)/2), coord(s.ph(h)/2), fill(color, opacity))
+}
+
+// Rect makes a rectangle centered at (x, y)
+func (s *SVG) Rect(x, y, w, h float64, color string, opacity ...float64) {
+	fmt.Fprintf(s.cur(), "<rect x=%q y=%q width=%q height=%q %s/>\n",
+		coord(s.px(x)-s.pw(w)/2), coord(s.py(y)-s.ph(h)/2), coord(s.pw(w)), coord(s.ph(h)), fill(color, opacity))
+}
+
+// Square makes a square centered at (x, y) with sides w
+func (s *SVG) Square(x, y, w float64, color string, opacity ...float64) {
+	side := s.pw(w)
+	fmt.Fprintf(s.cur(), "<rect x=%q y=%q width=%q height=%q %s/>\n",
+		coord(s.px(x)-side/2), coord(s.py(y)-side/2), coord(side), coord(side), fill(color, opacity))
+}
+
+// Line makes a line from (x1, y1) to (x2, y2); the width is a percentage of the canvas width
+func (s *SVG) Line(x1, y1, x2, y2, size float64, color string, opacity ...float64) {
+	fmt.Fprintf(s.cur(), "<line x1=%q y1=%q x2=%q y2=%q %s/>\n",
+		coord(s.px(x1)), coord(s.py(y1)), coord(s.px(x2)), coord(s.py(y2)), stroke(color, s.pw(size), opacity))
+}
+
+// points formats a list of coordinates as SVG points
+func (s *SVG) points(x, y []float64) string {
+	n := min(len(x), len(y))
+	p := make([]string, n)
+	for i := 0; i < n; i++ {
+		p[i] = coord(s.px(x[i])) + "," + coord(s.py(y[i]))
+	}
+	return strings.Join(p, " ")
+}
+
+// Polygon makes a filled polygon
+func (s *SVG) Polygon(x, y []float64, color string, opacity ...float64) {
+	fmt.Fprintf(s.cur(), "<polygon points=%q %s/>\n", s.points(x, y), fill(color, opacity))
+}
+
+// Polyline makes connected line segments
+func (s *SVG) Polyline(x, y []float64, size float64, color string, opacity ...float64) {
+	fmt.Fprintf(s.cur(), "<polyline points=%q %s/>\n", s.points(x, y), stroke(color, s.pw(size), opacity))
+}
+
+// Arc makes an arc centered at (x, y) from angle a1 to a2 (degrees),
+// counterclockwise; a sweep of 360 or more is a full ellipse. As in deck,
+// both dimensions are relative to the canvas width.
+func (s *SVG) Arc(x, y, w, h, size, a1, a2 float64, color string, opacity ...float64) {
+	cx, cy := s.px(x), s.py(y)
+	rx, ry := s.pw(w)/2, s.pw(h)/2
+	t1, t2 := a1*math.Pi/180, a2*math.Pi/180
+	x1, y1 := cx+rx*math.Cos(t1), cy-ry*math.Sin(t1)
+	x2, y2 := cx+rx*math.Cos(t2), cy-ry*math.Sin(t2)
+	if math.Abs(a2-a1) >= 360 { // a full circle, as two half arcs
+		tm := t1 + math.Pi
+		xm, ym := cx+rx*math.Cos(tm), cy-ry*math.Sin(tm)
+		fmt.Fprintf(s.cur(), "<path d=\"M%s,%s A%s,%s 0 0 0 %s,%s A%s,%s 0 0 0 %s,%s\" %s/>\n",
+			coord(x1), coord(y1), coord(rx), coord(ry), coord(xm), coord(ym),
+			coord(rx), coord(ry), coord(x1), coord(y1), stroke(color, s.pw(size), opacity))
+		return
+	}
+	large := 0
+	if math.Mod(math.Mod(a2-a1, 360)+360, 360) > 180 {
+		large = 1
+	}
+	fmt.Fprintf(s.cur(), "<path d=\"M%s,%s A%s,%s 0 %d 0 %s,%s\" %s/>\n",
+		coord(x1), coord(y1), coord(rx), coord(ry), large, coord(x2), coord(y2), stroke(color, s.pw(size), opacity))
+}
+
+// Curve makes a quadratic Bezier curve from (x1, y1) to (x3, y3), with control point (x2, y2)
+func (s *SVG) Curve(x1, y1, x2, y2, x3, y3, size float64, color string, opacity ...float64) {
+	fmt.Fprintf(s.cur(), "<path d=\"M%s,%s Q%s,%s %s,%s\" %s/>\n",
+		coord(s.px(x1)), coord(s.py(y1)), coord(s.px(x2)), coord(s.py(y2)), coord(s.px(x3)), coord(s.py(y3)), stroke(color, s.pw(size), opacity))
+}
+
+// Image places an image (w and h in pixels) centered at (x, y), scaled by a percentage
+func (s *SVG) Image(x, y float64, w, h int, scale float64, name string) {
+	if scale <= 0 {
+		scale = 100
+	}
+	iw, ih := float64(w)*scale/100, float64(h)*scale/100
+	fmt.Fprintf(s.cur(), "<image x=%q y=%q width=%q height=%q xlink:href=%q/>\n",
+		coord(s.px(x)-iw/2), coord(s.py(y)-ih/2), coord(iw), coord(ih), xmlesc(name))
+}
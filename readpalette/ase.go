@@ -0,0 +1,159 @@
+package readpalette
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"unicode/utf16"
+)
+
+const aseMagic = "ASEF"
+
+// ASE block types
+const (
+	aseGroupStart = 0xc001
+	aseGroupEnd   = 0xc002
+	aseColor      = 0x0001
+)
+
+// aseMaxBlock bounds the length of a block: the largest, a swatch with
+// the longest name and a CMYK color, is a little over 128K
+const aseMaxBlock = 1 << 20
+
+// ReadASE reads an Adobe Swatch Exchange file. Each group becomes
+// a palette named for the group; colors outside a group are placed
+// in a palette called name. Swatch names are not kept, but a swatch
+// named by a translucent color of the same red, green and blue, as
+// written by WriteASE, keeps its alpha as #rrggbbaa.
+func ReadASE(r io.Reader, name string) (spalette, error) {
+	var header struct {
+		Magic  [4]byte
+		Major  uint16
+		Minor  uint16
+		Blocks uint32
+	}
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+		return nil, fmt.Errorf("ASE header: %v", err)
+	}
+	if string(header.Magic[:]) != aseMagic {
+		return nil, fmt.Errorf("missing %q signature", aseMagic)
+	}
+	p := make(spalette)
+	group := palkey(name)
+	for i := uint32(0); i < header.Blocks; i++ {
+		var btype uint16
+		var blen uint32
+		if err := binary.Read(r, binary.BigEndian, &btype); err != nil {
+			return nil, fmt.Errorf("block %d: %v", i, err)
+		}
+		if err := binary.Read(r, binary.BigEndian, &blen); err != nil {
+			return nil, fmt.Errorf("block %d: %v", i, err)
+		}
+		if blen > aseMaxBlock {
+			return nil, fmt.Errorf("block %d: length %d is too large", i, blen)
+		}
+		var data bytes.Buffer
+		if _, err := io.CopyN(&data, r, int64(blen)); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, fmt.Errorf("block %d: %v", i, err)
+		}
+		br := bytes.NewReader(data.Bytes())
+		switch btype {
+		case aseGroupStart:
+			gname, err := aseString(br)
+			if err != nil {
+				return nil, fmt.Errorf("block %d: %v", i, err)
+			}
+			group = palkey(gname)
+		case aseGroupEnd:
+			group = palkey(name)
+		case aseColor:
+			sname, err := aseString(br)
+			if err != nil {
+				return nil, fmt.Errorf("block %d: %v", i, err)
+			}
+			c, err := aseRGB(br)
+			if err != nil {
+				return nil, fmt.Errorf("block %d: %v", i, err)
+			}
+			p[group] = append(p[group], withalpha(c, sname))
+		}
+	}
+	return p, nil
+}
+
+// aseString reads a length-prefixed, null terminated UTF-16 string
+func aseString(r io.Reader) (string, error) {
+	var n uint16
+	if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+		return "", err
+	}
+	u := make([]uint16, n)
+	if err := binary.Read(r, binary.BigEndian, u); err != nil {
+		return "", err
+	}
+	if n > 0 && u[n-1] == 0 {
+		u = u[:n-1]
+	}
+	return string(utf16.Decode(u)), nil
+}
+
+// aseRGB reads a color model and its values, returning #rrggbb
+func aseRGB(r io.Reader) (string, error) {
+	var model [4]byte
+	if err := binary.Read(r, binary.BigEndian, &model); err != nil {
+		return "", err
+	}
+	var v []float32
+	switch string(model[:]) {
+	case "RGB ", "LAB ":
+		v = make([]float32, 3)
+	case "CMYK":
+		v = make([]float32, 4)
+	case "Gray":
+		v = make([]float32, 1)
+	default:
+		return "", fmt.Errorf("unknown color model %q", model[:])
+	}
+	if err := binary.Read(r, binary.BigEndian, v); err != nil {
+		return "", err
+	}
+	var red, green, blue float64
+	switch string(model[:]) {
+	case "RGB ":
+		red, green, blue = float64(v[0]), float64(v[1]), float64(v[2])
+	case "LAB ":
+		l := float64(v[0])
+		if l <= 1 {
+			l *= 100 // L is stored as a fraction
+		}
+		red, green, blue = labToRGB(l, float64(v[1]), float64(v[2]))
+	case "CMYK":
+		k := 1 - float64(v[3])
+		red = (1 - float64(v[0])) * k
+		green = (1 - float64(v[1])) * k
+		blue = (1 - float64(v[2])) * k
+	case "Gray":
+		red, green, blue = float64(v[0]), float64(v[0]), float64(v[0])
+	}
+	return hexcolor(unit8(red), unit8(green), unit8(blue)), nil
+}
+
+// withalpha adds the alpha of a swatch named by a translucent color
+// to the swatch's #rrggbb, when the name is the same color
+func withalpha(c, name string) string {
+	v, err := ParseColor(name)
+	if err != nil || v.A == 0xff || hexcolor(v.R, v.G, v.B) != c {
+		return c
+	}
+	return fmt.Sprintf("%s%02x", c, v.A)
+}
+
+// unit8 scales a 0..1 value to 0..255
+func unit8(v float64) uint8 {
+	return uint8(math.Round(math.Max(0, math.Min(1, v)) * 255))
+}
@@ -0,0 +1,206 @@
+package readpalette
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ParseError describes a color that could not be parsed.
+// Line and Column locate the color in native palette text;
+// for palettes read from other formats, they are zero and
+// Palette and Index identify the color instead.
+type ParseError struct {
+	Line, Column int
+	Palette      string
+	Index        int
+	Text         string
+	Err          error
+}
+
+func (e *ParseError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d, column %d: %q: %v", e.Line, e.Column, e.Text, e.Err)
+	}
+	return fmt.Sprintf("palette %s, color %d: %q: %v", e.Palette, e.Index+1, e.Text, e.Err)
+}
+
+func (e *ParseError) Unwrap() error { return e.Err }
+
+// Errors returned (wrapped in a ParseError) for bad colors
+var (
+	ErrSyntax   = errors.New("unknown color syntax")
+	ErrName     = errors.New("unknown color name")
+	ErrRange    = errors.New("value out of range")
+	ErrArgCount = errors.New("wrong number of values")
+)
+
+// ParseColor parses a color in any of the forms accepted by deck markup:
+// #rgb, #rrggbb, #rrggbbaa, rgb(r,g,b[,a]), hsv(h,s,v[,a]), hsl(h,s,l[,a])
+// and SVG color names. r, g, b are 0-255; h is 0-360; s, v, l are 0-100;
+// the optional alpha is 0-1.
+func ParseColor(s string) (color.NRGBA, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "#") {
+		return parseHexColor(s)
+	}
+	if i := strings.IndexByte(s, '('); i > 0 {
+		return parseFunc(strings.ToLower(s[:i]), s[i:])
+	}
+	if c, ok := svgcolors[strings.ToLower(s)]; ok {
+		return c, nil
+	}
+	return color.NRGBA{}, ErrName
+}
+
+// parseHexColor parses #rgb, #rrggbb and #rrggbbaa
+func parseHexColor(s string) (color.NRGBA, error) {
+	h := s[1:]
+	x, err := strconv.ParseUint(h, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, ErrSyntax
+	}
+	switch len(h) {
+	case 3:
+		r, g, b := uint8(x>>8&0xf), uint8(x>>4&0xf), uint8(x&0xf)
+		return color.NRGBA{R: r * 0x11, G: g * 0x11, B: b * 0x11, A: 0xff}, nil
+	case 6:
+		r, g, b := rgb(uint32(x))
+		return color.NRGBA{R: r, G: g, B: b, A: 0xff}, nil
+	case 8:
+		r, g, b := rgb(uint32(x >> 8))
+		return color.NRGBA{R: r, G: g, B: b, A: uint8(x)}, nil
+	}
+	return color.NRGBA{}, ErrSyntax
+}
+
+// parseFunc parses the arguments of rgb(), hsv() and hsl()
+func parseFunc(fn, args string) (color.NRGBA, error) {
+	if !strings.HasPrefix(args, "(") || !strings.HasSuffix(args, ")") {
+		return color.NRGBA{}, ErrSyntax
+	}
+	fields := strings.Split(args[1:len(args)-1], ",")
+	if len(fields) != 3 && len(fields) != 4 {
+		return color.NRGBA{}, ErrArgCount
+	}
+	v := make([]float64, len(fields))
+	for i, f := range fields {
+		f = strings.TrimSuffix(strings.TrimSpace(f), "%")
+		n, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return color.NRGBA{}, ErrSyntax
+		}
+		v[i] = n
+	}
+	alpha := uint8(0xff)
+	if len(v) == 4 {
+		if v[3] < 0 || v[3] > 1 {
+			return color.NRGBA{}, ErrRange
+		}
+		alpha = uint8(math.Round(v[3] * 255))
+	}
+	var r, g, b float64
+	switch fn {
+	case "rgb":
+		for _, c := range v[:3] {
+			if c < 0 || c > 255 {
+				return color.NRGBA{}, ErrRange
+			}
+		}
+		return color.NRGBA{R: uint8(math.Round(v[0])), G: uint8(math.Round(v[1])), B: uint8(math.Round(v[2])), A: alpha}, nil
+	case "hsv", "hsl":
+		if v[0] < 0 || v[0] > 360 || v[1] < 0 || v[1] > 100 || v[2] < 0 || v[2] > 100 {
+			return color.NRGBA{}, ErrRange
+		}
+		if fn == "hsv" {
+			r, g, b = hsv2rgb(v[0], v[1]/100, v[2]/100)
+		} else {
+			r, g, b = hsl2rgb(v[0], v[1]/100, v[2]/100)
+		}
+	default:
+		return color.NRGBA{}, ErrSyntax
+	}
+	return color.NRGBA{R: unit8(r), G: unit8(g), B: unit8(b), A: alpha}, nil
+}
+
+// hsv2rgb converts hue (0-360), saturation and value (0-1) to rgb (0-1)
+func hsv2rgb(h, s, v float64) (float64, float64, float64) {
+	c := v * s
+	return hcx(h, c, v-c)
+}
+
+// hsl2rgb converts hue (0-360), saturation and lightness (0-1) to rgb (0-1)
+func hsl2rgb(h, s, l float64) (float64, float64, float64) {
+	c := (1 - math.Abs(2*l-1)) * s
+	return hcx(h, c, l-c/2)
+}
+
+// hcx computes rgb from hue, chroma and the lightness offset
+func hcx(h, c, m float64) (float64, float64, float64) {
+	h = math.Mod(h, 360) / 60
+	x := c * (1 - math.Abs(math.Mod(h, 2)-1))
+	var r, g, b float64
+	switch {
+	case h < 1:
+		r, g, b = c, x, 0
+	case h < 2:
+		r, g, b = x, c, 0
+	case h < 3:
+		r, g, b = 0, c, x
+	case h < 4:
+		r, g, b = 0, x, c
+	case h < 5:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+	return r + m, g + m, b + m
+}
+
+// field is a white space separated token and its 1-based column
+type field struct {
+	text   string
+	column int
+}
+
+// fields splits a line at white space outside of parentheses,
+// so that "rgb(1, 2, 3)" remains one field
+func fields(line string) []field {
+	var f []field
+	depth, start := 0, -1
+	for i, c := range line {
+		switch {
+		case c == '(':
+			depth++
+		case c == ')' && depth > 0:
+			depth--
+		case (c == ' ' || c == '\t') && depth == 0:
+			if start >= 0 {
+				f = append(f, field{line[start:i], start + 1})
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		f = append(f, field{line[start:], start + 1})
+	}
+	return f
+}
+
+// SplitColors splits a list of colors at white space outside of
+// parentheses, so that "rgb(10, 20, 30)" remains one color
+func SplitColors(s string) []string {
+	f := fields(strings.Join(strings.Fields(s), " "))
+	colors := make([]string, len(f))
+	for i, c := range f {
+		colors[i] = c.text
+	}
+	return colors
+}
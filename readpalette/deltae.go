@@ -0,0 +1,112 @@
+package readpalette
+
+import (
+	"image/color"
+	"math"
+)
+
+// DeltaE2000 returns the CIEDE2000 color difference between two CIELAB colors
+func DeltaE2000(lab1, lab2 [3]float64) float64 {
+	l1, a1, b1 := lab1[0], lab1[1], lab1[2]
+	l2, a2, b2 := lab2[0], lab2[1], lab2[2]
+
+	cbar := (math.Hypot(a1, b1) + math.Hypot(a2, b2)) / 2
+	c7 := math.Pow(cbar, 7)
+	g := 0.5 * (1 - math.Sqrt(c7/(c7+math.Pow(25, 7))))
+	a1p, a2p := a1*(1+g), a2*(1+g)
+	c1p, c2p := math.Hypot(a1p, b1), math.Hypot(a2p, b2)
+	h1p, h2p := hueAngle(b1, a1p), hueAngle(b2, a2p)
+
+	dl := l2 - l1
+	dc := c2p - c1p
+	var dh float64
+	if c1p*c2p != 0 {
+		dh = h2p - h1p
+		switch {
+		case dh > 180:
+			dh -= 360
+		case dh < -180:
+			dh += 360
+		}
+	}
+	dH := 2 * math.Sqrt(c1p*c2p) * math.Sin(rad(dh/2))
+
+	lbar := (l1 + l2) / 2
+	cbarp := (c1p + c2p) / 2
+	hbar := h1p + h2p
+	if c1p*c2p != 0 {
+		switch {
+		case math.Abs(h1p-h2p) <= 180:
+			hbar /= 2
+		case hbar < 360:
+			hbar = (hbar + 360) / 2
+		default:
+			hbar = (hbar - 360) / 2
+		}
+	}
+	t := 1 - 0.17*math.Cos(rad(hbar-30)) + 0.24*math.Cos(rad(2*hbar)) +
+		0.32*math.Cos(rad(3*hbar+6)) - 0.20*math.Cos(rad(4*hbar-63))
+	dtheta := 30 * math.Exp(-math.Pow((hbar-275)/25, 2))
+	cp7 := math.Pow(cbarp, 7)
+	rc := 2 * math.Sqrt(cp7/(cp7+math.Pow(25, 7)))
+	l50 := (lbar - 50) * (lbar - 50)
+	sl := 1 + 0.015*l50/math.Sqrt(20+l50)
+	sc := 1 + 0.045*cbarp
+	sh := 1 + 0.015*cbarp*t
+	rt := -math.Sin(rad(2*dtheta)) * rc
+
+	return math.Sqrt((dl/sl)*(dl/sl) + (dc/sc)*(dc/sc) + (dH/sh)*(dH/sh) + rt*(dc/sc)*(dH/sh))
+}
+
+// hueAngle returns the angle of (a, b) in degrees, 0..360
+func hueAngle(b, a float64) float64 {
+	if a == 0 && b == 0 {
+		return 0
+	}
+	h := math.Atan2(b, a) * 180 / math.Pi
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+func rad(d float64) float64 { return d * math.Pi / 180 }
+
+// Matcher finds the nearest palette color by CIEDE2000 distance,
+// remembering previous answers
+type Matcher struct {
+	colors []color.NRGBA
+	labs   [][3]float64
+	cache  map[color.NRGBA]int
+}
+
+// NewMatcher makes a Matcher for a list of colors
+func NewMatcher(colors []color.NRGBA) *Matcher {
+	m := &Matcher{colors: colors, labs: make([][3]float64, len(colors)), cache: make(map[color.NRGBA]int)}
+	for i, c := range colors {
+		m.labs[i] = ToSpace(c, CIELab)
+	}
+	return m
+}
+
+// Index returns the index of the palette color nearest to c
+func (m *Matcher) Index(c color.NRGBA) int {
+	c.A = 0xff
+	if i, ok := m.cache[c]; ok {
+		return i
+	}
+	lab := ToSpace(c, CIELab)
+	best, dist := 0, math.Inf(1)
+	for i, p := range m.labs {
+		if d := DeltaE2000(lab, p); d < dist {
+			best, dist = i, d
+		}
+	}
+	m.cache[c] = best
+	return best
+}
+
+// Nearest returns the palette color nearest to c
+func (m *Matcher) Nearest(c color.NRGBA) color.NRGBA {
+	return m.colors[m.Index(c)]
+}
@@ -0,0 +1,203 @@
+package readpalette
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"sort"
+)
+
+// Swatch is a color and the share of the image's pixels nearest to it
+type Swatch struct {
+	Color color.NRGBA
+	Share float64
+}
+
+// maxSamples limits the number of pixels examined in large images
+const maxSamples = 250000
+
+// sample is a distinct color, its Lab coordinates and its pixel count
+type sample struct {
+	lab    [3]float64
+	weight float64
+}
+
+// samples returns the distinct colors of an image with their counts,
+// stepping over pixels of large images. Transparent pixels are skipped.
+func samples(img image.Image) []sample {
+	b := img.Bounds()
+	step := 1
+	for (b.Dx()/step)*(b.Dy()/step) > maxSamples {
+		step++
+	}
+	counts := make(map[color.NRGBA]float64)
+	for y := b.Min.Y; y < b.Max.Y; y += step {
+		for x := b.Min.X; x < b.Max.X; x += step {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.A == 0 {
+				continue
+			}
+			c.A = 0xff
+			counts[c]++
+		}
+	}
+	s := make([]sample, 0, len(counts))
+	for c, n := range counts {
+		s = append(s, sample{ToSpace(c, CIELab), n})
+	}
+	// map iteration order is random; sort for repeatable results
+	sort.Slice(s, func(i, j int) bool {
+		for k := 0; k < 3; k++ {
+			if s[i].lab[k] != s[j].lab[k] {
+				return s[i].lab[k] < s[j].lab[k]
+			}
+		}
+		return false
+	})
+	return s
+}
+
+// Extract returns the n dominant colors of an image, found by
+// "mediancut" or "kmeans" clustering in CIELAB, ordered by share.
+func Extract(img image.Image, n int, method string) ([]Swatch, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("need at least one color, got %d", n)
+	}
+	s := samples(img)
+	if len(s) == 0 {
+		return nil, fmt.Errorf("image has no opaque pixels")
+	}
+	var centers [][3]float64
+	switch method {
+	case "mediancut":
+		centers = medianCut(s, n)
+	case "kmeans":
+		centers = kmeans(s, medianCut(s, n))
+	default:
+		return nil, fmt.Errorf("unknown method %q", method)
+	}
+	return swatches(s, centers), nil
+}
+
+// swatches assigns samples to their nearest centers and computes shares
+func swatches(s []sample, centers [][3]float64) []Swatch {
+	weights := make([]float64, len(centers))
+	total := 0.0
+	for _, p := range s {
+		weights[nearest(p.lab, centers)] += p.weight
+		total += p.weight
+	}
+	sw := make([]Swatch, 0, len(centers))
+	for i, c := range centers {
+		if weights[i] == 0 {
+			continue
+		}
+		sw = append(sw, Swatch{Color: FromSpace(c, CIELab, 0xff), Share: weights[i] / total})
+	}
+	sort.SliceStable(sw, func(i, j int) bool { return sw[i].Share > sw[j].Share })
+	return sw
+}
+
+// nearest returns the index of the center closest to v
+func nearest(v [3]float64, centers [][3]float64) int {
+	best, dist := 0, math.Inf(1)
+	for i, c := range centers {
+		d := (v[0]-c[0])*(v[0]-c[0]) + (v[1]-c[1])*(v[1]-c[1]) + (v[2]-c[2])*(v[2]-c[2])
+		if d < dist {
+			best, dist = i, d
+		}
+	}
+	return best
+}
+
+// mean returns the weighted mean of samples
+func mean(s []sample) [3]float64 {
+	var m [3]float64
+	total := 0.0
+	for _, p := range s {
+		for k := range m {
+			m[k] += p.lab[k] * p.weight
+		}
+		total += p.weight
+	}
+	for k := range m {
+		m[k] /= total
+	}
+	return m
+}
+
+// medianCut repeatedly splits the box with the widest range at the
+// weighted median of its widest axis, until there are n boxes,
+// returning the mean of each box
+func medianCut(s []sample, n int) [][3]float64 {
+	boxes := [][]sample{append([]sample(nil), s...)}
+	for len(boxes) < n {
+		bi, axis, width := -1, 0, 0.0
+		for i, b := range boxes {
+			if len(b) < 2 {
+				continue
+			}
+			for k := 0; k < 3; k++ {
+				lo, hi := math.Inf(1), math.Inf(-1)
+				for _, p := range b {
+					lo, hi = math.Min(lo, p.lab[k]), math.Max(hi, p.lab[k])
+				}
+				if hi-lo > width {
+					bi, axis, width = i, k, hi-lo
+				}
+			}
+		}
+		if bi < 0 {
+			break // every box holds a single color
+		}
+		b := boxes[bi]
+		sort.SliceStable(b, func(i, j int) bool { return b[i].lab[axis] < b[j].lab[axis] })
+		total := 0.0
+		for _, p := range b {
+			total += p.weight
+		}
+		cut, acc := len(b)-1, 0.0
+		for i, p := range b[:len(b)-1] {
+			acc += p.weight
+			if acc >= total/2 {
+				cut = i + 1
+				break
+			}
+		}
+		boxes[bi] = b[:cut]
+		boxes = append(boxes, b[cut:])
+	}
+	centers := make([][3]float64, len(boxes))
+	for i, b := range boxes {
+		centers[i] = mean(b)
+	}
+	return centers
+}
+
+// kmeans refines the centers by Lloyd's algorithm
+func kmeans(s []sample, centers [][3]float64) [][3]float64 {
+	for iter := 0; iter < 32; iter++ {
+		sums := make([][4]float64, len(centers))
+		for _, p := range s {
+			i := nearest(p.lab, centers)
+			for k := 0; k < 3; k++ {
+				sums[i][k] += p.lab[k] * p.weight
+			}
+			sums[i][3] += p.weight
+		}
+		moved := 0.0
+		for i, sum := range sums {
+			if sum[3] == 0 {
+				continue
+			}
+			c := [3]float64{sum[0] / sum[3], sum[1] / sum[3], sum[2] / sum[3]}
+			moved = math.Max(moved, math.Abs(c[0]-centers[i][0])+math.Abs(c[1]-centers[i][1])+math.Abs(c[2]-centers[i][2]))
+			centers[i] = c
+		}
+		if moved < 0.01 {
+			break
+		}
+	}
+	return centers
+}
@@ -0,0 +1,92 @@
+package readpalette
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+)
+
+// harmonies maps harmony rules to hue rotations in degrees
+var harmonies = map[string][]float64{
+	"complementary":       {0, 180},
+	"split-complementary": {0, 150, 210},
+	"analogous":           {-30, 0, 30},
+	"triadic":             {0, 120, 240},
+	"tetradic":            {0, 60, 180, 240},
+	"square":              {0, 90, 180, 270},
+}
+
+// Harmonies returns the names of the harmony rules
+func Harmonies() []string {
+	names := make([]string, 0, len(harmonies))
+	for k := range harmonies {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Harmony returns the colors related to seed by a harmony rule.
+// Hues are rotated in OKLCh, so the lightness and chroma of the
+// seed are kept and the colors are of similar perceived weight.
+func Harmony(seed color.NRGBA, rule string) ([]color.NRGBA, error) {
+	angles, ok := harmonies[rule]
+	if !ok {
+		return nil, fmt.Errorf("unknown harmony %q", rule)
+	}
+	lab := ToSpace(seed, OKLab)
+	chroma := math.Hypot(lab[1], lab[2])
+	hue := math.Atan2(lab[2], lab[1])
+	colors := make([]color.NRGBA, len(angles))
+	for i, a := range angles {
+		h := hue + a*math.Pi/180
+		colors[i] = FromSpace([3]float64{lab[0], chroma * math.Cos(h), chroma * math.Sin(h)}, OKLab, seed.A)
+	}
+	return colors, nil
+}
+
+// Ramp returns n colors evenly spaced along the path through stops,
+// interpolated in the given color space. The first and last colors
+// are the first and last stops.
+func Ramp(stops []color.NRGBA, n int, sp Space) ([]color.NRGBA, error) {
+	if len(stops) < 2 {
+		return nil, fmt.Errorf("a ramp needs at least two colors")
+	}
+	if n < 2 {
+		return nil, fmt.Errorf("a ramp needs at least two steps")
+	}
+	segments := len(stops) - 1
+	colors := make([]color.NRGBA, n)
+	for i := 0; i < n; i++ {
+		t := float64(i) / float64(n-1) * float64(segments)
+		s := min(int(t), segments-1)
+		f := t - float64(s)
+		c0, c1 := stops[s], stops[s+1]
+		v0, v1 := ToSpace(c0, sp), ToSpace(c1, sp)
+		var v [3]float64
+		for j := range v {
+			v[j] = v0[j] + (v1[j]-v0[j])*f
+		}
+		alpha := float64(c0.A) + (float64(c1.A)-float64(c0.A))*f
+		colors[i] = FromSpace(v, sp, uint8(math.Round(alpha)))
+	}
+	return colors, nil
+}
+
+// HexColor returns the #rrggbb form of a color, or #rrggbbaa if it is not opaque
+func HexColor(c color.NRGBA) string {
+	if c.A == 0xff {
+		return hexcolor(c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+}
+
+// HexColors returns the string forms of colors, suitable for a palette
+func HexColors(colors []color.NRGBA) []string {
+	s := make([]string, len(colors))
+	for i, c := range colors {
+		s[i] = HexColor(c)
+	}
+	return s
+}
@@ -0,0 +1,63 @@
+package readpalette
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const gplMagic = "GIMP Palette"
+
+// ReadGPL reads a GIMP palette. The palette is named by the
+// "Name:" header, or by name if the header is missing.
+func ReadGPL(r io.Reader, name string) (spalette, error) {
+	scanner := bufio.NewScanner(r)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("empty GIMP palette")
+	}
+	if strings.TrimSpace(scanner.Text()) != gplMagic {
+		return nil, fmt.Errorf("missing %q header", gplMagic)
+	}
+	var colors []string
+	for n := 2; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		if v, ok := strings.CutPrefix(line, "Name:"); ok {
+			name = strings.TrimSpace(v)
+			continue
+		}
+		if strings.HasPrefix(line, "Columns:") {
+			continue
+		}
+		f := strings.Fields(line)
+		if len(f) < 3 {
+			return nil, fmt.Errorf("line %d: expected r g b, got %q", n, line)
+		}
+		var v [3]uint8
+		for i := 0; i < 3; i++ {
+			c, err := strconv.ParseUint(f[i], 10, 8)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: bad color component %q", n, f[i])
+			}
+			v[i] = uint8(c)
+		}
+		colors = append(colors, hexcolor(v[0], v[1], v[2]))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return spalette{palkey(name): colors}, nil
+}
+
+// palkey makes a palette name usable in the native format,
+// which separates fields with white space
+func palkey(name string) string {
+	return strings.Join(strings.Fields(name), "-")
+}
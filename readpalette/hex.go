@@ -0,0 +1,36 @@
+package readpalette
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReadHex reads a Lospec hex palette: one rrggbb value per line.
+// The file carries no name, so the palette is called name.
+func ReadHex(r io.Reader, name string) (spalette, error) {
+	scanner := bufio.NewScanner(r)
+	var colors []string
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimPrefix(strings.TrimSpace(scanner.Text()), "#")
+		if len(line) == 0 {
+			continue
+		}
+		if len(line) != 6 {
+			return nil, fmt.Errorf("line %d: expected rrggbb, got %q", n, line)
+		}
+		if _, err := strconv.ParseUint(line, 16, 32); err != nil {
+			return nil, fmt.Errorf("line %d: expected rrggbb, got %q", n, line)
+		}
+		colors = append(colors, "#"+strings.ToLower(line))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(colors) == 0 {
+		return nil, ErrEmpty
+	}
+	return spalette{palkey(name): colors}, nil
+}
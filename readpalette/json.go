@@ -0,0 +1,60 @@
+package readpalette
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ReadJSON reads a JSON palette, either an object mapping
+// palette names to color lists:
+//
+//	{"mist-gb": ["#2d1b00", "#1e606e", "#5ab9a8", "#c4f0c2"]}
+//
+// or a single palette in the lospec.com form, where the colors may omit the '#':
+//
+//	{"name": "mist-gb", "colors": ["2d1b00", "1e606e", "5ab9a8", "c4f0c2"]}
+func ReadJSON(r io.Reader, name string) (spalette, error) {
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, err
+	}
+	if _, ok := raw["colors"]; ok {
+		if _, ok := raw["name"]; ok {
+			return readLospec(raw, name)
+		}
+	}
+	p := make(spalette)
+	for k, v := range raw {
+		var colors []string
+		if err := json.Unmarshal(v, &colors); err != nil {
+			return nil, fmt.Errorf("palette %q: %v", k, err)
+		}
+		p[palkey(k)] = colors
+	}
+	return p, nil
+}
+
+// readLospec decodes the lospec.com form
+func readLospec(raw map[string]json.RawMessage, name string) (spalette, error) {
+	var lname string
+	var lcolors []string
+	if err := json.Unmarshal(raw["name"], &lname); err != nil {
+		return nil, fmt.Errorf("name: %v", err)
+	}
+	if err := json.Unmarshal(raw["colors"], &lcolors); err != nil {
+		return nil, fmt.Errorf("colors: %v", err)
+	}
+	if len(lname) > 0 {
+		name = lname
+	}
+	colors := make([]string, len(lcolors))
+	for i, c := range lcolors {
+		if !strings.HasPrefix(c, "#") {
+			c = "#" + c
+		}
+		colors[i] = strings.ToLower(c)
+	}
+	return spalette{palkey(name): colors}, nil
+}
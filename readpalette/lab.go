@@ -0,0 +1,142 @@
+package readpalette
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+)
+
+// Space names a color space used for interpolation
+type Space string
+
+// Interpolation spaces
+const (
+	OKLab  Space = "oklab"
+	CIELab Space = "lab"
+	SRGB   Space = "srgb"
+)
+
+// ParseSpace returns the color space named by s
+func ParseSpace(s string) (Space, error) {
+	switch sp := Space(s); sp {
+	case OKLab, CIELab, SRGB:
+		return sp, nil
+	}
+	return "", fmt.Errorf("unknown color space %q", s)
+}
+
+// D50 reference white
+const (
+	d50x = 0.96422
+	d50y = 1.0
+	d50z = 0.82521
+)
+
+// labToRGB converts CIELAB (D50) to sRGB components in 0..1
+func labToRGB(l, a, b float64) (float64, float64, float64) {
+	fy := (l + 16) / 116
+	fx := fy + a/500
+	fz := fy - b/200
+	x := d50x * labInv(fx)
+	y := d50y * labInv(fy)
+	z := d50z * labInv(fz)
+	// XYZ (D50, Bradford adapted) to linear sRGB
+	r := 3.1338561*x - 1.6168667*y - 0.4906146*z
+	g := -0.9787684*x + 1.9161415*y + 0.0334540*z
+	bl := 0.0719453*x - 0.2289914*y + 1.4052427*z
+	return gamma(r), gamma(g), gamma(bl)
+}
+
+// rgbToLab converts sRGB components in 0..1 to CIELAB (D50)
+func rgbToLab(r, g, b float64) (float64, float64, float64) {
+	r, g, b = linear(r), linear(g), linear(b)
+	// linear sRGB to XYZ (D50, Bradford adapted)
+	x := 0.4360747*r + 0.3850649*g + 0.1430804*b
+	y := 0.2225045*r + 0.7168786*g + 0.0606169*b
+	z := 0.0139322*r + 0.0971045*g + 0.7141733*b
+	fx := labF(x / d50x)
+	fy := labF(y / d50y)
+	fz := labF(z / d50z)
+	return 116*fy - 16, 500 * (fx - fy), 200 * (fy - fz)
+}
+
+func labF(t float64) float64 {
+	const delta = 6.0 / 29.0
+	if t > delta*delta*delta {
+		return math.Cbrt(t)
+	}
+	return t/(3*delta*delta) + 4.0/29.0
+}
+
+func labInv(t float64) float64 {
+	const delta = 6.0 / 29.0
+	if t > delta {
+		return t * t * t
+	}
+	return 3 * delta * delta * (t - 4.0/29.0)
+}
+
+// rgbToOKLab converts sRGB components in 0..1 to OKLab
+func rgbToOKLab(r, g, b float64) (float64, float64, float64) {
+	r, g, b = linear(r), linear(g), linear(b)
+	l := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*b)
+	m := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*b)
+	s := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*b)
+	return 0.2104542553*l + 0.7936177850*m - 0.0040720468*s,
+		1.9779984951*l - 2.4285922050*m + 0.4505937099*s,
+		0.0259040371*l + 0.7827717662*m - 0.8086757660*s
+}
+
+// okLabToRGB converts OKLab to sRGB components in 0..1
+func okLabToRGB(l, a, b float64) (float64, float64, float64) {
+	lc := l + 0.3963377774*a + 0.2158037573*b
+	mc := l - 0.1055613458*a - 0.0638541728*b
+	sc := l - 0.0894841775*a - 1.2914855480*b
+	lc, mc, sc = lc*lc*lc, mc*mc*mc, sc*sc*sc
+	return gamma(4.0767416621*lc - 3.3077115913*mc + 0.2309699292*sc),
+		gamma(-1.2684380046*lc + 2.6097574011*mc - 0.3413193965*sc),
+		gamma(-0.0041960863*lc - 0.7034186147*mc + 1.7076147010*sc)
+}
+
+// linear removes the sRGB transfer function
+func linear(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// gamma applies the sRGB transfer function to a linear value
+func gamma(v float64) float64 {
+	if v <= 0.0031308 {
+		return 12.92 * v
+	}
+	return 1.055*math.Pow(v, 1/2.4) - 0.055
+}
+
+// ToSpace returns the coordinates of a color in the given space
+func ToSpace(c color.NRGBA, sp Space) [3]float64 {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	switch sp {
+	case OKLab:
+		l, a, b := rgbToOKLab(r, g, b)
+		return [3]float64{l, a, b}
+	case CIELab:
+		l, a, b := rgbToLab(r, g, b)
+		return [3]float64{l, a, b}
+	}
+	return [3]float64{r, g, b}
+}
+
+// FromSpace returns the color at the coordinates in the given space,
+// clipped to the sRGB gamut
+func FromSpace(v [3]float64, sp Space, alpha uint8) color.NRGBA {
+	r, g, b := v[0], v[1], v[2]
+	switch sp {
+	case OKLab:
+		r, g, b = okLabToRGB(v[0], v[1], v[2])
+	case CIELab:
+		r, g, b = labToRGB(v[0], v[1], v[2])
+	}
+	return color.NRGBA{R: unit8(r), G: unit8(g), B: unit8(b), A: alpha}
+}
@@ -2,10 +2,13 @@ package readpalette
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
 	"image/color"
 	"io"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 )
@@ -13,6 +16,31 @@ import (
 type spalette map[string][]string
 type rgbpalette map[string][]color.NRGBA
 
+// Format names a palette file format
+type Format string
+
+// Supported palette formats
+const (
+	Native Format = "native" // name #rrggbb #rrggbb ...
+	GPL    Format = "gpl"    // GIMP palette
+	ASE    Format = "ase"    // Adobe Swatch Exchange
+	Hex    Format = "hex"    // Lospec hex, one rrggbb per line
+	JSON   Format = "json"   // {"name": ["#rrggbb", ...], ...}
+	CSS    Format = "css"    // CSS custom properties (write only)
+	Deck   Format = "deck"   // deck markup swatch sheet (write only)
+)
+
+// extensions maps file extensions to formats
+var extensions = map[string]Format{
+	".gpl":  GPL,
+	".ase":  ASE,
+	".hex":  Hex,
+	".json": JSON,
+	".pal":  Native,
+	".css":  CSS,
+	".xml":  Deck,
+}
+
 func rgb(x uint32) (uint8, uint8, uint8) {
 	r := x & 0xff0000 >> 16
 	g := x & 0x00ff00 >> 8
@@ -20,46 +48,240 @@ func rgb(x uint32) (uint8, uint8, uint8) {
 	return uint8(r), uint8(g), uint8(b)
 }
 
+// hexcolor returns the #rrggbb form of a color
+func hexcolor(r, g, b uint8) string {
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+}
+
+// ParseFormat returns the format named by s
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case Native, GPL, ASE, Hex, JSON, CSS, Deck:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown palette format %q", s)
+}
+
+// DetectFormat determines the format of a palette file,
+// first by its extension, then by the leading bytes of its contents
+func DetectFormat(filename string, head []byte) Format {
+	if f, ok := extensions[strings.ToLower(filepath.Ext(filename))]; ok {
+		return f
+	}
+	switch {
+	case bytes.HasPrefix(head, []byte(aseMagic)):
+		return ASE
+	case bytes.HasPrefix(head, []byte(gplMagic)):
+		return GPL
+	}
+	h := bytes.TrimSpace(head)
+	if len(h) > 0 && (h[0] == '{') {
+		return JSON
+	}
+	if ishex(h) {
+		return Hex
+	}
+	return Native
+}
+
+// ishex reports whether every non-blank line is an rrggbb value,
+// with an optional leading '#'
+func ishex(b []byte) bool {
+	lines := 0
+	for _, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimPrefix(strings.TrimSpace(line), "#")
+		if len(line) == 0 {
+			continue
+		}
+		if len(line) != 6 {
+			return false
+		}
+		if _, err := strconv.ParseUint(line, 16, 32); err != nil {
+			return false
+		}
+		lines++
+	}
+	return lines > 0
+}
+
+// palname makes a palette name from a file name
+func palname(filename string) string {
+	base := filepath.Base(filename)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// ErrEmpty is returned when a palette file holds no palettes
+var ErrEmpty = errors.New("no palettes")
+
+// Read reads a palette in the specified format; name is used for
+// formats that do not carry their own palette name
+func Read(r io.Reader, f Format, name string) (spalette, error) {
+	p, err := read(r, f, name)
+	if err == nil && len(p) == 0 {
+		return nil, ErrEmpty
+	}
+	return p, err
+}
+
+func read(r io.Reader, f Format, name string) (spalette, error) {
+	switch f {
+	case Native:
+		return ReadString(r)
+	case GPL:
+		return ReadGPL(r, name)
+	case ASE:
+		return ReadASE(r, name)
+	case Hex:
+		return ReadHex(r, name)
+	case JSON:
+		return ReadJSON(r, name)
+	case CSS, Deck:
+		return nil, fmt.Errorf("%s palettes are write only", f)
+	}
+	return nil, fmt.Errorf("unknown palette format %q", f)
+}
+
+// ReadString reads palettes in the native format:
+// a name followed by its colors, one palette per line.
+// Lines beginning with '#' are comments.
 func ReadString(r io.Reader) (spalette, error) {
 	scanner := bufio.NewScanner(r)
 	p := make(spalette)
 	for scanner.Scan() {
-		args := strings.Fields(scanner.Text())
+		args := fields(scanner.Text())
 		l := len(args)
-		if l < 2 {
+		if l < 2 || iscomment(args) {
 			continue
 		}
-		name := args[0]
-		p[name] = args[1:]
+		name := args[0].text
+		colors := make([]string, l-1)
+		for i, a := range args[1:] {
+			colors[i] = a.text
+		}
+		p[name] = colors
 	}
 	return p, scanner.Err()
 }
 
+// ReadRGB reads native palettes as RGB values. Colors that cannot be
+// parsed are reported on standard error and left out of the palette.
 func ReadRGB(r io.Reader) (rgbpalette, error) {
+	return readRGB(r, false)
+}
 
-	palette, err := ReadString(r)
-	if err != nil {
-		return nil, err
-	}
+// ReadRGBStrict reads native palettes as RGB values, stopping at the
+// first color that cannot be parsed with a *ParseError giving its location.
+func ReadRGBStrict(r io.Reader) (rgbpalette, error) {
+	return readRGB(r, true)
+}
 
+// iscomment reports whether a line is a comment
+func iscomment(args []field) bool {
+	return strings.HasPrefix(args[0].text, "#")
+}
+
+func readRGB(r io.Reader, strict bool) (rgbpalette, error) {
+	scanner := bufio.NewScanner(r)
 	rp := make(rgbpalette)
-	for name, value := range palette {
-		colors := make([]color.NRGBA, len(value))
-		i := 0
-		for _, c := range value {
-			if len(c) != 7 {
-				continue // must be #nnnnnn
+	for line := 1; scanner.Scan(); line++ {
+		args := fields(scanner.Text())
+		if len(args) < 2 || iscomment(args) {
+			continue
+		}
+		colors := make([]color.NRGBA, 0, len(args)-1)
+		for _, a := range args[1:] {
+			c, err := ParseColor(a.text)
+			if err != nil {
+				perr := &ParseError{Line: line, Column: a.column, Palette: args[0].text, Text: a.text, Err: err}
+				if strict {
+					return nil, perr
+				}
+				fmt.Fprintf(os.Stderr, "%v\n", perr)
+				continue
 			}
-			x, err := strconv.ParseUint(c[1:], 16, 32)
+			colors = append(colors, c)
+		}
+		rp[args[0].text] = colors
+	}
+	return rp, scanner.Err()
+}
+
+// ToRGB converts a string palette to RGB values,
+// leaving out colors that cannot be parsed
+func ToRGB(palette spalette) rgbpalette {
+	rp, _ := toRGB(palette, false)
+	return rp
+}
+
+// ToRGBStrict converts a string palette to RGB values,
+// returning a *ParseError for the first color that cannot be parsed
+func ToRGBStrict(palette spalette) (rgbpalette, error) {
+	return toRGB(palette, true)
+}
+
+func toRGB(palette spalette, strict bool) (rgbpalette, error) {
+	rp := make(rgbpalette)
+	for _, name := range Names(palette) {
+		value := palette[name]
+		colors := make([]color.NRGBA, 0, len(value))
+		for i, s := range value {
+			c, err := ParseColor(s)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "%v\n", err)
+				if strict {
+					return nil, &ParseError{Palette: name, Index: i, Text: s, Err: err}
+				}
 				continue
 			}
-			r, g, b := rgb(uint32(x))
-			colors[i] = color.NRGBA{R: r, G: g, B: b, A: 0xff}
-			i++
+			colors = append(colors, c)
 		}
 		rp[name] = colors
 	}
 	return rp, nil
 }
+
+// LoadPalette reads a palette file, detecting its format
+func LoadPalette(filename string) (spalette, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	f := DetectFormat(filename, data)
+	p, err := Read(bytes.NewReader(data), f, palname(filename))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return p, nil
+}
+
+// LoadRGBPalette reads a palette file as RGB values, detecting its format
+func LoadRGBPalette(filename string) (rgbpalette, error) {
+	p, err := LoadPalette(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ToRGB(p), nil
+}
+
+// LoadRGBPaletteStrict reads a palette file as RGB values, detecting its format,
+// and returns a *ParseError for the first color that cannot be parsed
+func LoadRGBPaletteStrict(filename string) (rgbpalette, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var rp rgbpalette
+	if f := DetectFormat(filename, data); f == Native {
+		if rp, err = ReadRGBStrict(bytes.NewReader(data)); err == nil && len(rp) == 0 {
+			err = ErrEmpty
+		}
+	} else {
+		var p spalette
+		if p, err = Read(bytes.NewReader(data), f, palname(filename)); err == nil {
+			rp, err = ToRGBStrict(p)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return rp, nil
+}
@@ -0,0 +1,133 @@
+package readpalette
+
+import (
+	"embed"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// catalog holds the built-in palettes; each file's name is a tag
+// applied to the palettes it contains
+//
+//go:embed catalog/*.pal
+var catalog embed.FS
+
+// Registry is a set of named palettes, each with a list of tags
+type Registry struct {
+	palettes spalette
+	tags     map[string][]string
+}
+
+// NewRegistry makes an empty registry
+func NewRegistry() *Registry {
+	return &Registry{palettes: make(spalette), tags: make(map[string][]string)}
+}
+
+// Default makes a registry holding the built-in catalog
+func Default() *Registry {
+	r := NewRegistry()
+	files, err := catalog.ReadDir("catalog")
+	if err != nil {
+		panic(err) // the catalog is embedded; this cannot happen
+	}
+	for _, f := range files {
+		data, err := catalog.Open(path.Join("catalog", f.Name()))
+		if err != nil {
+			panic(err)
+		}
+		p, err := ReadString(data)
+		data.Close()
+		if err != nil {
+			panic(err)
+		}
+		r.Merge(p, strings.TrimSuffix(f.Name(), ".pal"))
+	}
+	return r
+}
+
+// Add adds (or replaces) a palette with the specified tags
+func (r *Registry) Add(name string, colors []string, tags ...string) {
+	r.palettes[name] = colors
+	r.tags[name] = append([]string(nil), tags...)
+}
+
+// Merge adds all palettes in p, with the specified tags
+func (r *Registry) Merge(p spalette, tags ...string) {
+	for name, colors := range p {
+		r.Add(name, colors, tags...)
+	}
+}
+
+// LoadFile merges the palettes from a file in any supported format,
+// tagged "user"; they replace built-in palettes of the same name.
+func (r *Registry) LoadFile(filename string) error {
+	p, err := LoadPalette(filename)
+	if err != nil {
+		return err
+	}
+	r.Merge(p, "user")
+	return nil
+}
+
+// Lookup returns the colors of the named palette
+func (r *Registry) Lookup(name string) ([]string, bool) {
+	c, ok := r.palettes[name]
+	return c, ok
+}
+
+// Resolve returns the colors of the named palette,
+// or, if there is no such palette, s itself as a single color
+func (r *Registry) Resolve(s string) []string {
+	if c, ok := r.palettes[s]; ok {
+		return c
+	}
+	return []string{s}
+}
+
+// Tags returns the tags of the named palette
+func (r *Registry) Tags(name string) []string {
+	return r.tags[name]
+}
+
+// Names returns the names of all palettes in sorted order
+func (r *Registry) Names() []string {
+	return Names(r.palettes)
+}
+
+// Palettes returns all the palettes
+func (r *Registry) Palettes() spalette {
+	return r.palettes
+}
+
+// Query selects palettes: Text matches any part of the name or a tag,
+// Tag must match a tag exactly, and Min and Max bound the number of
+// colors. Zero values match everything.
+type Query struct {
+	Text     string
+	Tag      string
+	Min, Max int
+}
+
+// Search returns the sorted names of the palettes matching q
+func (r *Registry) Search(q Query) []string {
+	var names []string
+	for name, colors := range r.palettes {
+		if q.Min > 0 && len(colors) < q.Min {
+			continue
+		}
+		if q.Max > 0 && len(colors) > q.Max {
+			continue
+		}
+		if len(q.Tag) > 0 && !slices.Contains(r.tags[name], q.Tag) {
+			continue
+		}
+		if len(q.Text) > 0 && !strings.Contains(name, q.Text) && !slices.Contains(r.tags[name], q.Text) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,273 @@
+package readpalette
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// Extension returns the customary file extension for a format
+func (f Format) Extension() string {
+	switch f {
+	case Native:
+		return ".pal"
+	case Deck:
+		return ".xml"
+	}
+	return "." + string(f)
+}
+
+// Single reports whether a format holds only one palette per file
+func (f Format) Single() bool {
+	return f == GPL || f == Hex
+}
+
+// Alpha reports whether a format keeps the alpha of translucent colors
+func (f Format) Alpha() bool {
+	return f != GPL && f != Hex
+}
+
+// ErrAlpha is returned (wrapped) when a format cannot keep the alpha of a color
+var ErrAlpha = errors.New("format has no alpha")
+
+// Names returns the palette names in sorted order
+func Names(p spalette) []string {
+	names := make([]string, 0, len(p))
+	for k := range p {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parsehex returns the red, green and blue components of a color
+func parsehex(c string) (uint8, uint8, uint8, error) {
+	v, err := ParseColor(c)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("color %q: %v", c, err)
+	}
+	return v.R, v.G, v.B, nil
+}
+
+// opaque returns the red, green and blue components of a color written
+// in a format without alpha, which must not drop that of a translucent color
+func opaque(c string, f Format) (uint8, uint8, uint8, error) {
+	v, err := ParseColor(c)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("color %q: %v", c, err)
+	}
+	if v.A != 0xff {
+		return 0, 0, 0, fmt.Errorf("color %q: %s %w", c, f, ErrAlpha)
+	}
+	return v.R, v.G, v.B, nil
+}
+
+// Write writes a palette in the specified format
+func Write(w io.Writer, p spalette, f Format) error {
+	switch f {
+	case Native:
+		return WriteString(w, p)
+	case GPL:
+		return WriteGPL(w, p)
+	case ASE:
+		return WriteASE(w, p)
+	case Hex:
+		return WriteHex(w, p)
+	case JSON:
+		return WriteJSON(w, p)
+	case CSS:
+		return WriteCSS(w, p)
+	case Deck:
+		return WriteDeck(w, p)
+	}
+	return fmt.Errorf("unknown palette format %q", f)
+}
+
+// WriteString writes palettes in the native format, one per line
+func WriteString(w io.Writer, p spalette) error {
+	width := 0
+	for name := range p {
+		if len(name) > width {
+			width = len(name)
+		}
+	}
+	for _, name := range Names(p) {
+		if _, err := fmt.Fprintf(w, "%-*s %s\n", width+1, name, strings.Join(p[name], " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// single returns the name and colors of a one palette map
+func single(p spalette, f Format) (string, []string, error) {
+	if len(p) != 1 {
+		return "", nil, fmt.Errorf("%s holds one palette, have %d", f, len(p))
+	}
+	for name, colors := range p {
+		return name, colors, nil
+	}
+	return "", nil, nil
+}
+
+// WriteGPL writes a single palette as a GIMP palette.
+// Translucent colors are an error wrapping ErrAlpha.
+func WriteGPL(w io.Writer, p spalette) error {
+	name, colors, err := single(p, GPL)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s\nName: %s\nColumns: %d\n#\n", gplMagic, name, len(colors))
+	for _, c := range colors {
+		r, g, b, err := opaque(c, GPL)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&buf, "%3d %3d %3d\t%s\n", r, g, b, c)
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// WriteHex writes a single palette as a Lospec hex file.
+// Translucent colors are an error wrapping ErrAlpha.
+func WriteHex(w io.Writer, p spalette) error {
+	_, colors, err := single(p, Hex)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	for _, c := range colors {
+		r, g, b, err := opaque(c, Hex)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(&buf, hexcolor(r, g, b)[1:])
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// WriteJSON writes palettes as a JSON object keyed by palette name
+func WriteJSON(w io.Writer, p spalette) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(map[string][]string(p))
+}
+
+// WriteASE writes palettes as an Adobe Swatch Exchange file,
+// each palette a group of RGB swatches named by their color, as written
+// in the palette; ReadASE takes the alpha of translucent colors from the name
+func WriteASE(w io.Writer, p spalette) error {
+	var body bytes.Buffer
+	nblocks := 0
+	for _, name := range Names(p) {
+		var group bytes.Buffer
+		aseWriteString(&group, name)
+		aseBlock(&body, aseGroupStart, group.Bytes())
+		nblocks++
+		for _, c := range p[name] {
+			r, g, b, err := parsehex(c)
+			if err != nil {
+				return err
+			}
+			var swatch bytes.Buffer
+			aseWriteString(&swatch, c)
+			swatch.WriteString("RGB ")
+			binary.Write(&swatch, binary.BigEndian, []float32{float32(r) / 255, float32(g) / 255, float32(b) / 255})
+			binary.Write(&swatch, binary.BigEndian, uint16(2)) // normal color
+			aseBlock(&body, aseColor, swatch.Bytes())
+			nblocks++
+		}
+		aseBlock(&body, aseGroupEnd, nil)
+		nblocks++
+	}
+	var buf bytes.Buffer
+	buf.WriteString(aseMagic)
+	binary.Write(&buf, binary.BigEndian, []uint16{1, 0})
+	binary.Write(&buf, binary.BigEndian, uint32(nblocks))
+	buf.Write(body.Bytes())
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// aseWriteString writes a length-prefixed, null terminated UTF-16 string
+func aseWriteString(buf *bytes.Buffer, s string) {
+	u := utf16.Encode([]rune(s + "\x00"))
+	binary.Write(buf, binary.BigEndian, uint16(len(u)))
+	binary.Write(buf, binary.BigEndian, u)
+}
+
+// aseBlock writes a block header followed by its data
+func aseBlock(buf *bytes.Buffer, btype uint16, data []byte) {
+	binary.Write(buf, binary.BigEndian, btype)
+	binary.Write(buf, binary.BigEndian, uint32(len(data)))
+	buf.Write(data)
+}
+
+// WriteCSS writes palettes as CSS custom properties, --name-n
+func WriteCSS(w io.Writer, p spalette) error {
+	var buf bytes.Buffer
+	buf.WriteString(":root {\n")
+	for _, name := range Names(p) {
+		for i, c := range p[name] {
+			fmt.Fprintf(&buf, "  --%s-%d: %s;\n", name, i, c)
+		}
+	}
+	buf.WriteString("}\n")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// swatch sheet layout, in percentages
+const (
+	swatchRows   = 10
+	swatchTop    = 92
+	swatchLeft   = 5
+	swatchNameW  = 25
+	swatchStep   = 9
+	swatchHeight = 5
+	swatchWidth  = 65
+)
+
+// WriteDeck writes palettes as a deck markup swatch sheet,
+// one row per palette, ten palettes per slide
+func WriteDeck(w io.Writer, p spalette) error {
+	var buf bytes.Buffer
+	names := Names(p)
+	buf.WriteString("<deck>\n")
+	for i := 0; i < len(names); i += swatchRows {
+		buf.WriteString("<slide bg=\"white\" fg=\"black\">\n")
+		y := float64(swatchTop)
+		for _, name := range names[i:min(i+swatchRows, len(names))] {
+			colors := p[name]
+			fmt.Fprintf(&buf, "<text xp=\"%v\" yp=\"%v\" sp=\"1.5\">%s</text>\n", swatchLeft, y-0.5, Escape(name))
+			if len(colors) > 0 {
+				sw := float64(swatchWidth) / float64(len(colors))
+				x := float64(swatchLeft+swatchNameW) + sw/2
+				for _, c := range colors {
+					fmt.Fprintf(&buf, "<rect xp=\"%.2f\" yp=\"%v\" wp=\"%.2f\" hp=\"%v\" color=%q/>\n", x, y, sw, swatchHeight, c)
+					fmt.Fprintf(&buf, "<text xp=\"%.2f\" yp=\"%v\" sp=\"0.8\" align=\"c\" font=\"mono\">%s</text>\n", x, y-float64(swatchHeight)/2-1.5, Escape(c))
+					x += sw
+				}
+			}
+			y -= swatchStep
+		}
+		buf.WriteString("</slide>\n")
+	}
+	buf.WriteString("</deck>\n")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Escape escapes markup characters in text
+func Escape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
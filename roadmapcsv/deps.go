@@ -0,0 +1,78 @@
+package roadmapcsv
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Dep is a connection from an item to the item with the id Dest,
+// leaving and arriving at percentages of the items, with a description
+type Dep struct {
+	Dest       string
+	BPct, EPct float64
+	Desc       string
+}
+
+// FormatDeps writes connections for the Connection column, separated by
+// semicolons, each an id, with optional begin and end percentages and a
+// description, as "gl" or "gl@0.5,0=handoff". Semicolons and
+// backslashes in descriptions are escaped with a backslash.
+func FormatDeps(deps []Dep) string {
+	var s []string
+	for _, d := range deps {
+		t := d.Dest
+		if d.BPct != 0 || d.EPct != 0 {
+			t += "@" + strconv.FormatFloat(d.BPct, 'f', -1, 64) + "," + strconv.FormatFloat(d.EPct, 'f', -1, 64)
+		}
+		if len(d.Desc) > 0 {
+			t += "=" + escaper.Replace(d.Desc)
+		}
+		s = append(s, t)
+	}
+	return strings.Join(s, ";")
+}
+
+// escaper escapes the separator in descriptions
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`)
+
+// ParseDeps reads connections from the Connection column
+func ParseDeps(s string) []Dep {
+	var deps []Dep
+	for _, t := range split(s) {
+		var d Dep
+		t, d.Desc, _ = strings.Cut(t, "=")
+		t, pct, _ := strings.Cut(t, "@")
+		d.Dest = strings.TrimSpace(t)
+		if len(d.Dest) == 0 {
+			continue
+		}
+		if b, e, ok := strings.Cut(pct, ","); ok {
+			d.BPct, _ = strconv.ParseFloat(strings.TrimSpace(b), 64)
+			d.EPct, _ = strconv.ParseFloat(strings.TrimSpace(e), 64)
+		}
+		deps = append(deps, d)
+	}
+	return deps
+}
+
+// split splits connections at semicolons, removing the escapes
+func split(s string) []string {
+	var parts []string
+	var b strings.Builder
+	escaped := false
+	for _, c := range s {
+		switch {
+		case escaped:
+			b.WriteRune(c)
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == ';':
+			parts = append(parts, b.String())
+			b.Reset()
+		default:
+			b.WriteRune(c)
+		}
+	}
+	return append(parts, b.String())
+}
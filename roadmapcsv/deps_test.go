@@ -0,0 +1,29 @@
+package roadmapcsv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDepsRoundTrip(t *testing.T) {
+	for _, deps := range [][]Dep{
+		{{Dest: "gl"}},
+		{{Dest: "gl", BPct: 0.5, Desc: "handoff"}, {Dest: "dm"}},
+		{{Dest: "gl", Desc: "handoff; review"}},
+		{{Dest: "gl", Desc: "  padded  "}, {Dest: "dm", Desc: `a\b;c\;d`}},
+		{{Dest: "gl", Desc: "x=y@1,2"}},
+	} {
+		s := FormatDeps(deps)
+		if got := ParseDeps(s); !reflect.DeepEqual(got, deps) {
+			t.Errorf("ParseDeps(%q) = %+v, want %+v", s, got, deps)
+		}
+	}
+}
+
+func TestParseDeps(t *testing.T) {
+	got := ParseDeps(" gl ;dm@0.5, 1=handoff")
+	want := []Dep{{Dest: "gl"}, {Dest: "dm", BPct: 0.5, EPct: 1, Desc: "handoff"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
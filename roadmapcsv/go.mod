@@ -0,0 +1,3 @@
+module github.com/ajstarks/utils/roadmapcsv
+
+go 1.21.6
@@ -0,0 +1,138 @@
+// Package roadmapcsv is the CSV form of roadmaps, written and read by
+// roadmap and converted to XML by rmcsv.
+//
+// Columns are found by their names in the header row; the first five are
+// those of earlier versions. A row with a scale is the roadmap: its title,
+// begin, end, scale, catpercent, vspace, itemheight, shape, header, fystart
+// and font. A row with no begin, duration or end begins a category, with
+// its name, color, shape, vspace, itemheight, bline and description (one
+// line per catdesc item); the rows that follow are its items. Empty rows
+// are ignored. Milestones and blines are "on".
+package roadmapcsv
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Columns are the columns, in the order written
+var Columns = []string{"Category/Item", "Begin", "Duration", "Id", "Connection",
+	"End", "Color", "Shape", "Milestone", "Align", "Description",
+	"Vspace", "Itemheight", "Bline", "Scale", "Catpercent", "Header", "Fystart", "Font"}
+
+// Kind is the kind of a row
+type Kind int
+
+// The kinds of rows
+const (
+	Item Kind = iota
+	Category
+	Roadmap
+)
+
+// Row holds the fields of a row, by column name
+type Row map[string]string
+
+// Kind tells what a row describes
+func (row Row) Kind() Kind {
+	switch {
+	case len(row["Scale"]) > 0:
+		return Roadmap
+	case len(row["Begin"]) == 0 && len(row["Duration"]) == 0 && len(row["End"]) == 0:
+		return Category
+	}
+	return Item
+}
+
+// Fields orders a row's fields by column
+func (row Row) Fields() []string {
+	f := make([]string, len(Columns))
+	for i, c := range Columns {
+		f[i] = row[c]
+	}
+	return f
+}
+
+// Number reads a number from a field; empty is zero
+func (row Row) Number(column string) (float64, error) {
+	s := strings.TrimSpace(row[column])
+	if len(s) == 0 {
+		return 0, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("bad %s %q", strings.ToLower(column), s)
+	}
+	return v, nil
+}
+
+// Num writes a number for a field, leaving zero empty
+func Num(v float64) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// Reader reads the rows of a roadmap
+type Reader struct {
+	r       *csv.Reader
+	columns []string
+}
+
+// NewReader makes a reader; the first row names the columns
+func NewReader(r io.Reader) *Reader {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	return &Reader{r: cr}
+}
+
+// Spaced reports whether the file has vspace or itemheight columns;
+// categories in files without them take the spacing of earlier versions
+func (r *Reader) Spaced() bool {
+	return slices.Contains(r.columns, "Vspace") || slices.Contains(r.columns, "Itemheight")
+}
+
+// Read returns the next row that is not empty, or io.EOF. After an error
+// in a row, reading may continue with the next.
+func (r *Reader) Read() (Row, error) {
+	for {
+		fields, err := r.r.Read()
+		if err != nil {
+			return nil, err
+		}
+		if r.columns == nil {
+			r.columns = header(fields)
+			continue
+		}
+		row := Row{}
+		empty := true
+		for i, c := range r.columns {
+			if i < len(fields) && len(c) > 0 {
+				row[c] = fields[i]
+				empty = empty && len(strings.TrimSpace(fields[i])) == 0
+			}
+		}
+		if !empty {
+			return row, nil
+		}
+	}
+}
+
+// header names the columns of a header row, matching names without
+// regard to case; columns with unknown names are ignored.
+func header(fields []string) []string {
+	columns := make([]string, len(fields))
+	for i, f := range fields {
+		for _, c := range Columns {
+			if strings.EqualFold(strings.TrimSpace(f), c) {
+				columns[i] = c
+			}
+		}
+	}
+	return columns
+}
@@ -0,0 +1,188 @@
+package scale
+
+import "math"
+
+// Log is a logarithmic scale. Its domain must not include or cross zero.
+type Log struct {
+	Domain [2]float64
+	Range  [2]float64
+	Clamp  bool
+	Base   float64 // default 10
+}
+
+// NewLog makes a base 10 log scale from the domain d0..d1 to the range r0..r1
+func NewLog(d0, d1, r0, r1 float64) *Log {
+	return &Log{Domain: [2]float64{d0, d1}, Range: [2]float64{r0, r1}, Base: 10}
+}
+
+// base returns the log base, defaulting to 10
+func (s *Log) base() float64 {
+	if s.Base <= 0 || s.Base == 1 {
+		return 10
+	}
+	return s.Base
+}
+
+// log and pow are the transform and its inverse; a negative
+// domain is mirrored, so that -100..-1 works like 1..100
+func (s *Log) log(v float64) float64 {
+	if s.Domain[0] < 0 {
+		return -math.Log(-v) / math.Log(s.base())
+	}
+	return math.Log(v) / math.Log(s.base())
+}
+
+func (s *Log) pow(v float64) float64 {
+	if s.Domain[0] < 0 {
+		return -math.Pow(s.base(), -v)
+	}
+	return math.Pow(s.base(), v)
+}
+
+// Map maps a domain value to the range
+func (s *Log) Map(v float64) float64 {
+	return apply(v, s.Domain, s.Range, s.Clamp, s.log)
+}
+
+// Invert maps a range value to the domain
+func (s *Log) Invert(v float64) float64 {
+	return invert(v, s.Domain, s.Range, s.Clamp, s.log, s.pow)
+}
+
+// Ticks returns ticks at the powers of the base within the domain.
+// When the domain spans fewer decades than n, ticks at 2 and 5 times
+// each power (base 10) are added; when it spans more, powers are skipped.
+func (s *Log) Ticks(n int) []Tick {
+	sign := 1.0
+	lo, hi := math.Min(s.Domain[0], s.Domain[1]), math.Max(s.Domain[0], s.Domain[1])
+	if lo < 0 {
+		sign, lo, hi = -1, -hi, -lo
+	}
+	if lo <= 0 {
+		return nil
+	}
+	b := s.base()
+	e0 := math.Floor(math.Log(lo)/math.Log(b) + 1e-9)
+	e1 := math.Ceil(math.Log(hi)/math.Log(b) - 1e-9)
+	decades := e1 - e0
+	mult := []float64{1}
+	if b == 10 && decades < float64(n)/2 {
+		mult = []float64{1, 2, 5}
+	}
+	step := math.Max(1, math.Ceil(decades/float64(max(n, 1))))
+	var t []Tick
+	for e := e0; e <= e1; e += step {
+		p := math.Pow(b, e)
+		for _, m := range mult {
+			v := m * p
+			if v < lo*(1-1e-9) || v > hi*(1+1e-9) {
+				continue
+			}
+			t = append(t, Tick{Value: sign * v, Label: Format(sign*v, math.Min(p, 1)), Step: math.Min(p, 1)})
+		}
+	}
+	if sign < 0 {
+		for i, j := 0, len(t)-1; i < j; i, j = i+1, j-1 {
+			t[i], t[j] = t[j], t[i]
+		}
+	}
+	return t
+}
+
+// Nice extends the domain to powers of the base; n is not used
+func (s *Log) Nice(n int) {
+	for i, v := range s.Domain {
+		e := s.log(v)
+		if v <= s.Domain[1-i] {
+			e = math.Floor(e + 1e-9)
+		} else {
+			e = math.Ceil(e - 1e-9)
+		}
+		s.Domain[i] = s.pow(e)
+	}
+}
+
+// Symlog is a symmetric log scale: linear near zero and logarithmic
+// away from it, so its domain may include zero and negative values.
+type Symlog struct {
+	Domain   [2]float64
+	Range    [2]float64
+	Clamp    bool
+	Constant float64 // extent of the linear region, default 1
+}
+
+// NewSymlog makes a symlog scale from the domain d0..d1 to the range r0..r1
+func NewSymlog(d0, d1, r0, r1 float64) *Symlog {
+	return &Symlog{Domain: [2]float64{d0, d1}, Range: [2]float64{r0, r1}, Constant: 1}
+}
+
+func (s *Symlog) constant() float64 {
+	if s.Constant <= 0 {
+		return 1
+	}
+	return s.Constant
+}
+
+func (s *Symlog) transform(v float64) float64 {
+	return math.Copysign(math.Log1p(math.Abs(v)/s.constant()), v)
+}
+
+func (s *Symlog) untransform(v float64) float64 {
+	return math.Copysign(math.Expm1(math.Abs(v))*s.constant(), v)
+}
+
+// Map maps a domain value to the range
+func (s *Symlog) Map(v float64) float64 {
+	return apply(v, s.Domain, s.Range, s.Clamp, s.transform)
+}
+
+// Invert maps a range value to the domain
+func (s *Symlog) Invert(v float64) float64 {
+	return invert(v, s.Domain, s.Range, s.Clamp, s.transform, s.untransform)
+}
+
+// Ticks returns about n nicely spaced ticks within the domain
+func (s *Symlog) Ticks(n int) []Tick {
+	return ticks(s.Domain[0], s.Domain[1], n)
+}
+
+// Nice extends the domain to nice round values, for about n ticks
+func (s *Symlog) Nice(n int) {
+	s.Domain = nice(s.Domain, n)
+}
+
+// Sqrt is a square root scale, suited to sizing areas by value.
+// Negative values map symmetrically.
+type Sqrt struct {
+	Domain [2]float64
+	Range  [2]float64
+	Clamp  bool
+}
+
+// NewSqrt makes a square root scale from the domain d0..d1 to the range r0..r1
+func NewSqrt(d0, d1, r0, r1 float64) *Sqrt {
+	return &Sqrt{Domain: [2]float64{d0, d1}, Range: [2]float64{r0, r1}}
+}
+
+func sqrt(v float64) float64   { return math.Copysign(math.Sqrt(math.Abs(v)), v) }
+func square(v float64) float64 { return math.Copysign(v*v, v) }
+
+// Map maps a domain value to the range
+func (s *Sqrt) Map(v float64) float64 {
+	return apply(v, s.Domain, s.Range, s.Clamp, sqrt)
+}
+
+// Invert maps a range value to the domain
+func (s *Sqrt) Invert(v float64) float64 {
+	return invert(v, s.Domain, s.Range, s.Clamp, sqrt, square)
+}
+
+// Ticks returns about n nicely spaced ticks within the domain
+func (s *Sqrt) Ticks(n int) []Tick {
+	return ticks(s.Domain[0], s.Domain[1], n)
+}
+
+// Nice extends the domain to nice round values, for about n ticks
+func (s *Sqrt) Nice(n int) {
+	s.Domain = nice(s.Domain, n)
+}
@@ -0,0 +1,74 @@
+package scale
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// NiceNum returns a "nice" number close to x: 1, 2, 5 or 10 times a power
+// of ten. If round is true, the nearest nice number is returned; otherwise
+// the smallest nice number not less than x. (Heckbert, "Nice Numbers for
+// Graph Labels", Graphics Gems, 1990)
+func NiceNum(x float64, round bool) float64 {
+	if x <= 0 {
+		return 0
+	}
+	exp := math.Floor(math.Log10(x))
+	f := x / math.Pow(10, exp)
+	var nf float64
+	if round {
+		switch {
+		case f < 1.5:
+			nf = 1
+		case f < 3:
+			nf = 2
+		case f < 7:
+			nf = 5
+		default:
+			nf = 10
+		}
+	} else {
+		switch {
+		case f <= 1:
+			nf = 1
+		case f <= 2:
+			nf = 2
+		case f <= 5:
+			nf = 5
+		default:
+			nf = 10
+		}
+	}
+	return nf * math.Pow(10, exp)
+}
+
+// NiceRange returns loose bounds that enclose lo..hi,
+// and the tick spacing for about n ticks between them
+func NiceRange(lo, hi float64, n int) (min, max, step float64) {
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	if lo == hi {
+		return lo, hi, 0
+	}
+	if n < 2 {
+		n = 2
+	}
+	r := NiceNum(hi-lo, false)
+	step = NiceNum(r/float64(n-1), true)
+	return math.Floor(lo/step) * step, math.Ceil(hi/step) * step, step
+}
+
+// Format formats a tick value with as many decimals as the tick spacing needs
+func Format(v, step float64) string {
+	decimals := 0
+	if step > 0 {
+		decimals = max(int(-math.Floor(math.Log10(step))), 0)
+	}
+	s := strconv.FormatFloat(v, 'f', decimals, 64)
+	if z, _ := strconv.ParseFloat(s, 64); z == 0 {
+		return strings.TrimPrefix(s, "-") // no negative zero
+	}
+	return s
+}
@@ -0,0 +1,136 @@
+// Package scale maps data values to drawing coordinates, with linear, log,
+// symlog, square root and time scales, and chooses "nice" axis ticks.
+package scale
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Tick is an axis tick: a domain value and its label; Step is the
+// spacing that sets the label's decimals, for formatting it again
+type Tick struct {
+	Value float64
+	Label string
+	Step  float64
+}
+
+// Scale maps values from a domain to a range, and back
+type Scale interface {
+	Map(v float64) float64
+	Invert(v float64) float64
+	Ticks(n int) []Tick
+	Nice(n int)
+}
+
+// Kinds lists the kinds of numeric scale
+var Kinds = []string{"linear", "log", "symlog", "sqrt"}
+
+// New makes a numeric scale of the named kind
+func New(kind string, d0, d1, r0, r1 float64) (Scale, error) {
+	switch kind {
+	case "linear":
+		return NewLinear(d0, d1, r0, r1), nil
+	case "log":
+		if d0*d1 <= 0 {
+			return nil, fmt.Errorf("log scale domain %v..%v includes zero", d0, d1)
+		}
+		return NewLog(d0, d1, r0, r1), nil
+	case "symlog":
+		return NewSymlog(d0, d1, r0, r1), nil
+	case "sqrt":
+		return NewSqrt(d0, d1, r0, r1), nil
+	}
+	return nil, fmt.Errorf("unknown scale %q (use %s)", kind, strings.Join(Kinds, ", "))
+}
+
+// Linear is a linear scale
+type Linear struct {
+	Domain [2]float64
+	Range  [2]float64
+	Clamp  bool // keep mapped values within the range
+}
+
+// NewLinear makes a linear scale from the domain d0..d1 to the range r0..r1
+func NewLinear(d0, d1, r0, r1 float64) *Linear {
+	return &Linear{Domain: [2]float64{d0, d1}, Range: [2]float64{r0, r1}}
+}
+
+// Map maps a domain value to the range
+func (s *Linear) Map(v float64) float64 {
+	return apply(v, s.Domain, s.Range, s.Clamp, identity)
+}
+
+// Invert maps a range value to the domain
+func (s *Linear) Invert(v float64) float64 {
+	return invert(v, s.Domain, s.Range, s.Clamp, identity, identity)
+}
+
+// Ticks returns about n nicely spaced ticks within the domain
+func (s *Linear) Ticks(n int) []Tick {
+	return ticks(s.Domain[0], s.Domain[1], n)
+}
+
+// Nice extends the domain to nice round values, for about n ticks
+func (s *Linear) Nice(n int) {
+	s.Domain = nice(s.Domain, n)
+}
+
+// identity is the transform of a linear scale
+func identity(v float64) float64 { return v }
+
+// apply maps v from the domain to the range after transforming both
+func apply(v float64, d, r [2]float64, clamp bool, f func(float64) float64) float64 {
+	t0, t1 := f(d[0]), f(d[1])
+	if t0 == t1 {
+		return (r[0] + r[1]) / 2
+	}
+	t := (f(v) - t0) / (t1 - t0)
+	if clamp {
+		t = math.Max(0, math.Min(1, t))
+	}
+	return r[0] + t*(r[1]-r[0])
+}
+
+// invert maps v from the range back to the domain; f transforms
+// domain values, and inv undoes the transform
+func invert(v float64, d, r [2]float64, clamp bool, f, inv func(float64) float64) float64 {
+	if r[0] == r[1] {
+		return d[0]
+	}
+	t := (v - r[0]) / (r[1] - r[0])
+	if clamp {
+		t = math.Max(0, math.Min(1, t))
+	}
+	t0, t1 := f(d[0]), f(d[1])
+	return inv(t0 + t*(t1-t0))
+}
+
+// nice extends a domain, in either direction, to loose nice bounds
+func nice(d [2]float64, n int) [2]float64 {
+	lo, hi, _ := NiceRange(d[0], d[1], n)
+	if d[0] > d[1] {
+		return [2]float64{hi, lo}
+	}
+	return [2]float64{lo, hi}
+}
+
+// ticks returns nice ticks between a and b, in either order
+func ticks(a, b float64, n int) []Tick {
+	lo, hi := math.Min(a, b), math.Max(a, b)
+	if lo == hi {
+		return []Tick{{Value: lo, Label: Format(lo, 0)}}
+	}
+	_, _, step := NiceRange(lo, hi, n)
+	var t []Tick
+	first := math.Ceil(lo/step - 1e-9)
+	for i := first; i*step <= hi+step*1e-9; i++ {
+		v := i * step
+		if v == 0 {
+			v = 0 // not -0
+		}
+		t = append(t, Tick{Value: v, Label: Format(v, step), Step: step})
+	}
+	return t
+}
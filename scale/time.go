@@ -0,0 +1,188 @@
+package scale
+
+import (
+	"math"
+	"time"
+)
+
+// Time is a linear scale of time
+type Time struct {
+	Domain [2]time.Time
+	Range  [2]float64
+	Clamp  bool
+}
+
+// TimeTick is a tick on a time axis; Layout formats its label
+type TimeTick struct {
+	Time   time.Time
+	Label  string
+	Layout string
+}
+
+// NewTime makes a time scale from the domain t0..t1 to the range r0..r1
+func NewTime(t0, t1 time.Time, r0, r1 float64) *Time {
+	return &Time{Domain: [2]time.Time{t0, t1}, Range: [2]float64{r0, r1}}
+}
+
+// seconds returns a time as seconds since the Unix epoch
+func seconds(t time.Time) float64 {
+	return float64(t.UnixNano()) / 1e9
+}
+
+// domain returns the domain in seconds
+func (s *Time) domain() [2]float64 {
+	return [2]float64{seconds(s.Domain[0]), seconds(s.Domain[1])}
+}
+
+// Map maps a time to the range
+func (s *Time) Map(t time.Time) float64 {
+	return apply(seconds(t), s.domain(), s.Range, s.Clamp, identity)
+}
+
+// Invert maps a range value to a time
+func (s *Time) Invert(v float64) time.Time {
+	sec := invert(v, s.domain(), s.Range, s.Clamp, identity, identity)
+	whole := math.Floor(sec)
+	return time.Unix(int64(whole), int64((sec-whole)*1e9)).In(s.Domain[0].Location())
+}
+
+// interval is a tick interval on a time axis
+type interval struct {
+	unit   string // second, minute, hour, day, week, month or year
+	n      int    // number of units
+	layout string // label layout
+}
+
+// approximate unit lengths, for choosing an interval
+var unitlength = map[string]time.Duration{
+	"second": time.Second,
+	"minute": time.Minute,
+	"hour":   time.Hour,
+	"day":    24 * time.Hour,
+	"week":   7 * 24 * time.Hour,
+	"month":  30 * 24 * time.Hour,
+	"year":   365 * 24 * time.Hour,
+}
+
+// intervals lists the tick intervals, from shortest to longest
+var intervals = []interval{
+	{"second", 1, "15:04:05"},
+	{"second", 5, "15:04:05"},
+	{"second", 15, "15:04:05"},
+	{"second", 30, "15:04:05"},
+	{"minute", 1, "15:04"},
+	{"minute", 5, "15:04"},
+	{"minute", 15, "15:04"},
+	{"minute", 30, "15:04"},
+	{"hour", 1, "15:04"},
+	{"hour", 3, "Jan 2 15:04"},
+	{"hour", 6, "Jan 2 15:04"},
+	{"hour", 12, "Jan 2 15:04"},
+	{"day", 1, "Jan 2"},
+	{"day", 2, "Jan 2"},
+	{"week", 1, "Jan 2"},
+	{"month", 1, "Jan 2006"},
+	{"month", 3, "Jan 2006"},
+	{"year", 1, "2006"},
+}
+
+func (iv interval) length() time.Duration {
+	return time.Duration(iv.n) * unitlength[iv.unit]
+}
+
+// choose picks the interval giving about n ticks over a span
+func choose(span time.Duration, n int) interval {
+	if n < 1 {
+		n = 1
+	}
+	target := float64(span) / float64(n)
+	year := float64(unitlength["year"])
+	if target > year {
+		return interval{"year", int(NiceNum(target/year, true)), "2006"}
+	}
+	best, diff := intervals[0], math.Inf(1)
+	for _, iv := range intervals {
+		if d := math.Abs(math.Log(float64(iv.length()) / target)); d < diff {
+			best, diff = iv, d
+		}
+	}
+	return best
+}
+
+// floor returns the start of the interval containing t
+func (iv interval) floor(t time.Time) time.Time {
+	y, m, d := t.Date()
+	loc := t.Location()
+	day := time.Date(y, m, d, 0, 0, 0, 0, loc)
+	switch iv.unit {
+	case "second", "minute", "hour":
+		step := iv.length()
+		return day.Add(t.Sub(day) / step * step)
+	case "day":
+		return time.Date(y, m, d-(d-1)%iv.n, 0, 0, 0, 0, loc)
+	case "week":
+		return day.AddDate(0, 0, -int(t.Weekday()))
+	case "month":
+		mi := int(m) - 1
+		return time.Date(y, time.Month(mi-mi%iv.n+1), 1, 0, 0, 0, 0, loc)
+	}
+	return time.Date(y-y%iv.n, 1, 1, 0, 0, 0, 0, loc)
+}
+
+// next returns the start of the following interval
+func (iv interval) next(t time.Time) time.Time {
+	switch iv.unit {
+	case "second", "minute", "hour":
+		return t.Add(iv.length())
+	case "day":
+		n := iv.floor(t.AddDate(0, 0, iv.n))
+		if !n.After(t) { // the count restarts each month
+			n = t.AddDate(0, 0, iv.n)
+		}
+		return n
+	case "week":
+		return t.AddDate(0, 0, 7*iv.n)
+	case "month":
+		return t.AddDate(0, iv.n, 0)
+	}
+	return t.AddDate(iv.n, 0, 0)
+}
+
+// bounds returns the domain in increasing order
+func (s *Time) bounds() (time.Time, time.Time) {
+	if s.Domain[1].Before(s.Domain[0]) {
+		return s.Domain[1], s.Domain[0]
+	}
+	return s.Domain[0], s.Domain[1]
+}
+
+// Ticks returns about n ticks at calendar boundaries within the domain,
+// labeled to suit their spacing
+func (s *Time) Ticks(n int) []TimeTick {
+	lo, hi := s.bounds()
+	iv := choose(hi.Sub(lo), n)
+	var t []TimeTick
+	for tt := iv.floor(lo); !tt.After(hi); tt = iv.next(tt) {
+		if tt.Before(lo) {
+			continue
+		}
+		t = append(t, TimeTick{Time: tt, Label: tt.Format(iv.layout), Layout: iv.layout})
+	}
+	return t
+}
+
+// Nice extends the domain to the tick boundaries for about n ticks
+func (s *Time) Nice(n int) {
+	lo, hi := s.bounds()
+	iv := choose(hi.Sub(lo), n)
+	nlo := iv.floor(lo)
+	nhi := iv.floor(hi)
+	if nhi.Before(hi) {
+		nhi = iv.next(nhi)
+	}
+	if s.Domain[1].Before(s.Domain[0]) {
+		s.Domain = [2]time.Time{nhi, nlo}
+	} else {
+		s.Domain = [2]time.Time{nlo, nhi}
+	}
+}
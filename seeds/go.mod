@@ -0,0 +1,3 @@
+module github.com/ajstarks/utils/seeds
+
+go 1.21.6
@@ -0,0 +1,27 @@
+// Package seeds gives the random seeds of a command's variations:
+// the seed itself for one, otherwise seeds derived from it, so that each
+// variation, labelled with its seed, can be made again on its own.
+// A zero seed is taken from the clock. Derived seeds are never negative,
+// so they also serve as seeds for math/rand/v2 sources.
+package seeds
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Int64 returns the seeds for n variations
+func Int64(seed int64, n int) []int64 {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	if n <= 1 {
+		return []int64{seed}
+	}
+	src := rand.New(rand.NewSource(seed))
+	s := make([]int64, n)
+	for i := range s {
+		s[i] = src.Int63()
+	}
+	return s
+}
@@ -0,0 +1,3 @@
+module github.com/ajstarks/utils/settings
+
+go 1.21.6
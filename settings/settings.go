@@ -0,0 +1,129 @@
+// Package settings reads a command's options from a file, so that a chart
+// can be made again with the same settings. A config file has one option
+// per line, named as on the command line, without the dash:
+//
+//	# dicechart settings
+//	unit = 10
+//	color = steelblue
+//	title = "Graduates, 1910"
+//
+// Blank lines and lines starting with # are ignored. Values may be
+// quoted, as Go strings; quote them to keep leading or trailing spaces.
+// Options given on the command line override the file.
+package settings
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Parse parses the command line like flag.Parse, adding two options:
+// -config reads options from a file, and -dumpconfig prints the
+// effective options in the config file format, and exits.
+func Parse() {
+	filename := flag.String("config", "", "read options from a config file")
+	dump := flag.Bool("dumpconfig", false, "print the options as a config file, and exit")
+	flag.Parse()
+
+	if len(*filename) > 0 {
+		if err := ReadFile(flag.CommandLine, *filename); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(2)
+		}
+	}
+	if *dump {
+		if err := Write(os.Stdout, flag.CommandLine, "config", "dumpconfig"); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+}
+
+// ReadFile sets options in fs from the named config file
+func ReadFile(fs *flag.FlagSet, filename string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return Read(fs, f, filename)
+}
+
+// Read sets options in fs from a config file; options already set,
+// on the command line, are left alone. Options given their default value
+// are also left unset, so that a theme may still change them, and a
+// dumped configuration reads back as it was. name is used in error messages.
+func Read(fs *flag.FlagSet, r io.Reader, name string) error {
+	explicit := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return fmt.Errorf("%s:%d: expected name = value", name, n)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		f := fs.Lookup(key)
+		if f == nil {
+			return fmt.Errorf("%s:%d: unknown option %q", name, n, key)
+		}
+		if strings.HasPrefix(value, `"`) {
+			s, err := strconv.Unquote(value)
+			if err != nil {
+				return fmt.Errorf("%s:%d: %s: bad quoted value %s", name, n, key, value)
+			}
+			value = s
+		}
+		if explicit[key] || value == f.DefValue {
+			continue
+		}
+		if err := fs.Set(key, value); err != nil {
+			return fmt.Errorf("%s:%d: %s: %v", name, n, key, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// Write writes the options in fs in the config file format, sorted by
+// name, each preceded by its usage; the options in skip are left out.
+func Write(w io.Writer, fs *flag.FlagSet, skip ...string) error {
+	var flags []*flag.Flag
+	fs.VisitAll(func(f *flag.Flag) {
+		for _, s := range skip {
+			if f.Name == s {
+				return
+			}
+		}
+		flags = append(flags, f)
+	})
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Name < flags[j].Name })
+
+	bw := bufio.NewWriter(w)
+	for i, f := range flags {
+		if i > 0 {
+			bw.WriteString("\n")
+		}
+		fmt.Fprintf(bw, "# %s\n%s = %s\n", strings.ReplaceAll(f.Usage, "\n", "\n# "), f.Name, quote(f.Value.String()))
+	}
+	return bw.Flush()
+}
+
+// quote quotes values that would not read back as written
+func quote(s string) string {
+	if len(s) == 0 || s != strings.TrimSpace(s) || strings.HasPrefix(s, `"`) || strings.ContainsAny(s, "\n\r") {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,3 @@
+module github.com/ajstarks/utils/source
+
+go 1.21.6
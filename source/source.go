@@ -0,0 +1,142 @@
+// Package source opens a command's input, named as a file, "-" for
+// the standard input, or an http or https URL. URLs are read with a
+// timeout, and may be cached on disk, to be read again until they are
+// older than a maximum age.
+package source
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Opener opens inputs
+type Opener struct {
+	Timeout  time.Duration // for reading URLs; 0 for none
+	MaxAge   time.Duration // how long a cached URL is used; 0 for no cache
+	CacheDir string        // where URLs are cached
+	Client   *http.Client  // if nil, a client with the timeout is used
+	Stdin    io.Reader     // if nil, the standard input is used
+}
+
+// Default opens inputs with a 30 second timeout, and no cache
+var Default = Opener{Timeout: 30 * time.Second, CacheDir: cachedir()}
+
+// cachedir is the default cache directory
+func cachedir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "ajstarks-utils")
+	}
+	return filepath.Join(dir, "ajstarks-utils")
+}
+
+// Open opens an input with the default opener
+func Open(name string) (io.ReadCloser, error) {
+	return Default.Open(name)
+}
+
+// Flags defines the -timeout and -maxage options, which set the
+// default opener when the command line is parsed
+func Flags() {
+	flag.DurationVar(&Default.Timeout, "timeout", Default.Timeout, "timeout for reading URLs")
+	flag.DurationVar(&Default.MaxAge, "maxage", Default.MaxAge, "use URLs cached for up to this long (0 for no cache)")
+}
+
+// IsURL reports whether an input names an http or https URL
+func IsURL(name string) bool {
+	return strings.HasPrefix(name, "http://") || strings.HasPrefix(name, "https://")
+}
+
+// Open opens a file, the standard input ("-" or ""), or a URL;
+// the caller closes it
+func (o Opener) Open(name string) (io.ReadCloser, error) {
+	switch {
+	case name == "-" || name == "":
+		if o.Stdin != nil {
+			return io.NopCloser(o.Stdin), nil
+		}
+		return io.NopCloser(os.Stdin), nil
+	case IsURL(name):
+		if o.MaxAge > 0 {
+			return o.cached(name)
+		}
+		return o.get(name)
+	}
+	return os.Open(name)
+}
+
+// Age returns how long ago a URL was cached, and whether it is cached
+func (o Opener) Age(url string) (time.Duration, bool) {
+	fi, err := os.Stat(o.cachefile(url))
+	if err != nil {
+		return 0, false
+	}
+	return time.Since(fi.ModTime()), true
+}
+
+// get reads a URL
+func (o Opener) get(url string) (io.ReadCloser, error) {
+	client := o.Client
+	if client == nil {
+		client = &http.Client{Timeout: o.Timeout}
+	}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unable to get network data for %s (%s)", url, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// cachefile names the cache file for a URL
+func (o Opener) cachefile(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(o.CacheDir, hex.EncodeToString(sum[:]))
+}
+
+// cached reads a URL from the cache, if it is recent enough,
+// otherwise from the network, updating the cache
+func (o Opener) cached(url string) (io.ReadCloser, error) {
+	filename := o.cachefile(url)
+	if age, ok := o.Age(url); ok && age < o.MaxAge {
+		return os.Open(filename)
+	}
+	body, err := o.get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	if err := os.MkdirAll(o.CacheDir, 0755); err != nil {
+		return nil, err
+	}
+	// write to a temporary file, so that readers never see a partial copy
+	tmp, err := os.CreateTemp(o.CacheDir, "partial-")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return nil, err
+	}
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		os.Remove(tmp.Name())
+		return nil, err
+	}
+	return os.Open(filename)
+}
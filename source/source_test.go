@@ -0,0 +1,144 @@
+package source
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// counter serves "response n" for the nth request, with the status
+// given by status, if set
+type counter struct {
+	hits   atomic.Int32
+	status atomic.Int32
+}
+
+func (c *counter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n := c.hits.Add(1)
+	if s := c.status.Load(); s != 0 {
+		w.WriteHeader(int(s))
+	}
+	fmt.Fprintf(w, "response %d", n)
+}
+
+// read opens an input and reads all of it
+func read(t *testing.T, o Opener, name string) (string, error) {
+	t.Helper()
+	r, err := o.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	return string(data), err
+}
+
+func TestFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "data.d")
+	if err := os.WriteFile(name, []byte("file data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := read(t, Opener{}, name)
+	if err != nil || got != "file data" {
+		t.Errorf("Open(%q) = %q, %v; want %q", name, got, err, "file data")
+	}
+	if _, err := read(t, Opener{}, filepath.Join(t.TempDir(), "missing")); !os.IsNotExist(err) {
+		t.Errorf("missing file: got %v, want not exist", err)
+	}
+}
+
+func TestStdin(t *testing.T) {
+	for _, name := range []string{"-", ""} {
+		o := Opener{Stdin: strings.NewReader("standard input")}
+		got, err := read(t, o, name)
+		if err != nil || got != "standard input" {
+			t.Errorf("Open(%q) = %q, %v; want %q", name, got, err, "standard input")
+		}
+	}
+}
+
+func TestURL(t *testing.T) {
+	c := &counter{}
+	ts := httptest.NewServer(c)
+	defer ts.Close()
+	got, err := read(t, Opener{Timeout: time.Second}, ts.URL)
+	if err != nil || got != "response 1" {
+		t.Errorf("Open(%q) = %q, %v; want %q", ts.URL, got, err, "response 1")
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	start := time.Now()
+	_, err := read(t, Opener{Timeout: 50 * time.Millisecond}, ts.URL)
+	if err == nil {
+		t.Fatal("no error from a server that does not answer")
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("timed out after %v, want about 50ms", d)
+	}
+}
+
+func TestCache(t *testing.T) {
+	c := &counter{}
+	ts := httptest.NewServer(c)
+	defer ts.Close()
+	o := Opener{Timeout: time.Second, MaxAge: time.Hour, CacheDir: t.TempDir()}
+
+	// a hit within the maximum age is read from the cache
+	for i := 0; i < 2; i++ {
+		got, err := read(t, o, ts.URL)
+		if err != nil || got != "response 1" {
+			t.Fatalf("read %d: got %q, %v; want %q", i+1, got, err, "response 1")
+		}
+	}
+	if n := c.hits.Load(); n != 1 {
+		t.Errorf("%d requests within the maximum age, want 1", n)
+	}
+
+	// after it, the URL is read again
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(o.cachefile(ts.URL), old, old); err != nil {
+		t.Fatal(err)
+	}
+	got, err := read(t, o, ts.URL)
+	if err != nil || got != "response 2" {
+		t.Errorf("after the maximum age: got %q, %v; want %q", got, err, "response 2")
+	}
+	if age, ok := o.Age(ts.URL); !ok || age > time.Minute {
+		t.Errorf("cache age %v, %v after reading again; want recent", age, ok)
+	}
+}
+
+func TestNotCached(t *testing.T) {
+	c := &counter{}
+	c.status.Store(http.StatusInternalServerError)
+	ts := httptest.NewServer(c)
+	defer ts.Close()
+	o := Opener{Timeout: time.Second, MaxAge: time.Hour, CacheDir: t.TempDir()}
+
+	if _, err := read(t, o, ts.URL); err == nil {
+		t.Fatal("no error for status 500")
+	}
+	if _, ok := o.Age(ts.URL); ok {
+		t.Error("status 500 was cached")
+	}
+	c.status.Store(0)
+	got, err := read(t, o, ts.URL)
+	if err != nil || got != "response 2" {
+		t.Errorf("after status 500: got %q, %v; want %q", got, err, "response 2")
+	}
+}
@@ -0,0 +1,196 @@
+// Package theme assigns fonts and colors to the roles in a chart, so that
+// charts made by different commands look alike. Themes are read from JSON
+// files; light, dark and print themes are built in. A theme file sets
+// any of the roles; the rest keep the command's defaults:
+//
+//	{
+//		"name": "brand",
+//		"background": "white",
+//		"foreground": "rgb(40,40,40)",
+//		"accent": ["#4e79a7", "#f28e2b", "#e15759"],
+//		"grid": "lightgray",
+//		"title": {"font": "sans", "size": 3},
+//		"label": {"font": "sans", "size": 1.5},
+//		"value": {"font": "mono", "size": 1.2}
+//	}
+//
+// An accent entry may also name a palette, standing for its colors.
+package theme
+
+import (
+	"embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/ajstarks/utils/readpalette"
+)
+
+// builtin holds the built-in themes, one per file
+//
+//go:embed builtin/*.json
+var builtin embed.FS
+
+// Font is the font and text size for a role
+type Font struct {
+	Name string  `json:"font,omitempty"`
+	Size float64 `json:"size,omitempty"`
+}
+
+// Theme assigns colors and fonts to roles; empty roles are left
+// to the command's defaults
+type Theme struct {
+	Name       string   `json:"name,omitempty"`
+	Background string   `json:"background,omitempty"`
+	Foreground string   `json:"foreground,omitempty"`
+	Accent     []string `json:"accent,omitempty"` // series colors, in order
+	Grid       string   `json:"grid,omitempty"`
+	Title      Font     `json:"title"`
+	Label      Font     `json:"label"`
+	Value      Font     `json:"value"`
+}
+
+// Names lists the built-in themes
+func Names() []string {
+	files, err := builtin.ReadDir("builtin")
+	if err != nil {
+		panic(err) // the themes are embedded; this cannot happen
+	}
+	names := make([]string, 0, len(files))
+	for _, f := range files {
+		names = append(names, strings.TrimSuffix(f.Name(), ".json"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Load reads a theme: a built-in theme by name, otherwise a JSON file.
+// Accent entries that name a palette are replaced by its colors.
+func Load(name string) (Theme, error) {
+	var t Theme
+	data, err := builtin.ReadFile(path.Join("builtin", name+".json"))
+	if err != nil {
+		if data, err = os.ReadFile(name); err != nil {
+			return t, fmt.Errorf("theme %q is not a file, or one of %s", name, strings.Join(Names(), ", "))
+		}
+	}
+	if err := json.Unmarshal(data, &t); err != nil {
+		return t, fmt.Errorf("%s: %v", name, err)
+	}
+	var accent []string
+	reg := readpalette.Default()
+	for _, c := range t.Accent {
+		accent = append(accent, reg.Resolve(c)...)
+	}
+	t.Accent = accent
+	if err := t.Check(); err != nil {
+		return t, fmt.Errorf("%s: %v", name, err)
+	}
+	return t, nil
+}
+
+// Check checks that the theme's colors are valid
+func (t Theme) Check() error {
+	colors := map[string]string{"background": t.Background, "foreground": t.Foreground, "grid": t.Grid}
+	for i, c := range t.Accent {
+		colors[fmt.Sprintf("accent %d", i+1)] = c
+	}
+	for role, c := range colors {
+		if len(c) == 0 {
+			continue
+		}
+		if _, err := readpalette.ParseColor(c); err != nil {
+			return fmt.Errorf("%s color %q: %v", role, c, err)
+		}
+	}
+	return nil
+}
+
+// Over returns the theme with its empty roles filled from base
+func (t Theme) Over(base Theme) Theme {
+	r := t
+	set(&r.Name, base.Name)
+	set(&r.Background, base.Background)
+	set(&r.Foreground, base.Foreground)
+	set(&r.Grid, base.Grid)
+	if len(r.Accent) == 0 {
+		r.Accent = base.Accent
+	}
+	r.Title = t.Title.over(base.Title)
+	r.Label = t.Label.over(base.Label)
+	r.Value = t.Value.over(base.Value)
+	return r
+}
+
+// over returns the font with its empty fields filled from base
+func (f Font) over(base Font) Font {
+	set(&f.Name, base.Name)
+	set(&f.Size, base.Size)
+	return f
+}
+
+// set sets *v to value, if *v is empty
+func set[T comparable](v *T, value T) {
+	var zero T
+	if *v == zero {
+		*v = value
+	}
+}
+
+// Color returns the ith accent color, cycling through them
+func (t Theme) Color(i int) string {
+	if len(t.Accent) == 0 {
+		return t.Foreground
+	}
+	return t.Accent[i%len(t.Accent)]
+}
+
+// SlideColors returns the background and foreground colors for a slide;
+// none if neither is set, leaving the deck's defaults
+func (t Theme) SlideColors() []string {
+	switch {
+	case len(t.Foreground) > 0:
+		bg := t.Background
+		set(&bg, "white")
+		return []string{bg, t.Foreground}
+	case len(t.Background) > 0:
+		return []string{t.Background}
+	}
+	return nil
+}
+
+// Choose loads the named theme over the command's defaults;
+// with no name, the defaults are used
+func Choose(name string, defaults Theme) (Theme, error) {
+	if len(name) == 0 {
+		return defaults, nil
+	}
+	t, err := Load(name)
+	if err != nil {
+		return defaults, err
+	}
+	return t.Over(defaults), nil
+}
+
+// Use sets *v from the theme, unless the named flag was set on the
+// command line, so that options override themes; empty theme values
+// are ignored
+func Use[T comparable](v *T, flagname string, value T) {
+	var zero T
+	if value == zero {
+		return
+	}
+	explicit := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == flagname {
+			explicit = true
+		}
+	})
+	if !explicit {
+		*v = value
+	}
+}